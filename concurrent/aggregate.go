@@ -0,0 +1,149 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import "context"
+
+// MapReduce applies the mapper on the items and combines the outputs into a single value, using
+// the given number of workers. The items are split into contiguous chunks, one per worker, that
+// each worker folds into a partial result; the partial results are then combined in input order.
+// The result is therefore the same on every run as long as combine is associative, even if it is
+// not commutative. MapReduce returns the zero value if there are no items.
+//
+// Failed items are handled according to the error policy, FailFast by default. Under Skip, they
+// are left out of the result; otherwise the zero value is returned along with the error. If ctx is
+// done, the processing is halted and the context's error is returned.
+func MapReduce[In any, Out any](ctx context.Context, workers int, items []In, mapper TransformActionWithError[In, Out], combine func(a, b Out) Out, opts ...Option) (Out, error) {
+	result, err := aggregate(ctx, workers, items, opts,
+		func(_ context.Context, acc partial[Out], value In) (partial[Out], error) {
+			output, err := mapper(value)
+			if err != nil {
+				return acc, err
+			}
+			if !acc.ok {
+				return partial[Out]{value: output, ok: true}, nil
+			}
+			return partial[Out]{value: combine(acc.value, output), ok: true}, nil
+		},
+		func(a, b partial[Out]) partial[Out] {
+			switch {
+			case !a.ok:
+				return b
+			case !b.ok:
+				return a
+			}
+			return partial[Out]{value: combine(a.value, b.value), ok: true}
+		},
+	)
+	return result.value, err
+}
+
+// ParallelReduce combines the items into a single value, using the given number of workers. It is
+// MapReduce without a mapper, and gives the same guarantees.
+func ParallelReduce[T any](ctx context.Context, workers int, items []T, combine func(a, b T) T, opts ...Option) (T, error) {
+	return MapReduce(ctx, workers, items, func(value T) (T, error) {
+		return value, nil
+	}, combine, opts...)
+}
+
+// GroupBy groups the items by the key returned by the key function, using the given number of
+// workers. Each worker groups a contiguous chunk of the items, and the groups are merged in input
+// order, so the items of every group keep their input order. A panic in the key function is
+// handled like a failed item in MapReduce.
+func GroupBy[K comparable, V any](ctx context.Context, workers int, items []V, key func(V) K, opts ...Option) (map[K][]V, error) {
+	groups, err := aggregate(ctx, workers, items, opts,
+		func(_ context.Context, acc map[K][]V, value V) (map[K][]V, error) {
+			if acc == nil {
+				acc = make(map[K][]V)
+			}
+			k := key(value)
+			acc[k] = append(acc[k], value)
+			return acc, nil
+		},
+		func(a, b map[K][]V) map[K][]V {
+			if a == nil {
+				return b
+			}
+			for k, values := range b {
+				a[k] = append(a[k], values...)
+			}
+			return a
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if groups == nil {
+		groups = make(map[K][]V)
+	}
+	return groups, nil
+}
+
+// partial is a partial result of MapReduce, which is only valid once a first output was folded in.
+type partial[T any] struct {
+	value T
+	ok    bool
+}
+
+// aggregate splits the items into contiguous chunks, one per worker, folds each chunk into an
+// accumulator starting from the zero value with add, and merges the accumulators in input order.
+// Every call to add runs like an action of a stage: it is reported to the observer, its panics are
+// recovered, and its failures are handled by the error policy, in which case the accumulator it
+// returned is discarded.
+func aggregate[In any, Acc any](ctx context.Context, workers int, items []In, opts []Option, add func(ctx context.Context, acc Acc, value In) (Acc, error), merge func(a, b Acc) Acc) (Acc, error) {
+	var result Acc
+	if len(items) == 0 {
+		return result, nil
+	}
+	config := newConfig(opts)
+	p := newPipeline(ctx, config)
+	defer p.cancel()
+
+	workers = min(max(workers, 1), len(items))
+	partials := make([]Acc, workers)
+	for worker := 0; worker < workers; worker++ {
+		chunk := items[worker*len(items)/workers : (worker+1)*len(items)/workers]
+		offset := worker * len(items) / workers
+		p.goroutine(func() {
+			var acc Acc
+			step := func(ctx context.Context, value In) (Acc, error) {
+				return add(ctx, acc, value)
+			}
+			for i, value := range chunk {
+				if p.ctx.Err() != nil {
+					return
+				}
+				input := item[In]{index: offset + i, value: value}
+				next, err := observe(p, 0, worker, 0, step, input)
+				if err != nil {
+					p.fail(&ItemError{Index: input.index, Stage: 0, Input: value, Err: err})
+					continue
+				}
+				acc = next
+			}
+			partials[worker] = acc
+		})
+	}
+	p.wg.Wait()
+
+	if err := p.err(); err != nil {
+		return result, err
+	}
+	result = partials[0]
+	for _, acc := range partials[1:] {
+		result = merge(result, acc)
+	}
+	return result, nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+func sum(a, b int) int {
+	return a + b
+}
+
+func TestParallelReduce(t *testing.T) {
+	items := make([]int, 1000)
+	expected := 0
+	for i := range items {
+		items[i] = i
+		expected += i
+	}
+
+	for _, workers := range []int{0, 1, 3, 8, 2000} {
+		result, err := concurrent.ParallelReduce(context.Background(), workers, items, sum)
+
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result != expected {
+			t.Errorf("Expected %d with %d workers, got %d", expected, workers, result)
+		}
+	}
+}
+
+func TestParallelReduceEmpty(t *testing.T) {
+	result, err := concurrent.ParallelReduce(context.Background(), 4, nil, sum)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if result != 0 {
+		t.Errorf("Expected 0, got %d", result)
+	}
+}
+
+func TestMapReduceDeterministic(t *testing.T) {
+	items := make([]int, 100)
+	var expected string
+	for i := range items {
+		items[i] = i
+		expected += strconv.Itoa(i) + ","
+	}
+	// Concatenation is associative but not commutative, so any reordering would show.
+	concat := func(a, b string) string { return a + b }
+	format := func(n int) (string, error) { return strconv.Itoa(n) + ",", nil }
+
+	for i := 0; i < 20; i++ {
+		result, err := concurrent.MapReduce(context.Background(), 7, items, format, concat)
+
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result != expected {
+			t.Fatalf("Expected %q, got %q", expected, result)
+		}
+	}
+}
+
+func TestMapReduceError(t *testing.T) {
+	items := []int{2, 4, 5, 6}
+
+	_, err := concurrent.MapReduce(context.Background(), 2, items, failOnOdd, sum)
+
+	var itemErr *concurrent.ItemError
+	if !errors.As(err, &itemErr) || !errors.Is(err, errOdd) {
+		t.Fatalf("Expected an *ItemError wrapping %v, got %v", errOdd, err)
+	}
+	if itemErr.Index != 2 || itemErr.Input != 5 {
+		t.Errorf("Expected item 2 with input 5 to fail, got item %d with input %v", itemErr.Index, itemErr.Input)
+	}
+
+	result, err := concurrent.MapReduce(context.Background(), 2, items, failOnOdd, sum,
+		concurrent.WithErrorPolicy(concurrent.Skip))
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if result != 12 {
+		t.Errorf("Expected the failed item to be skipped and get 12, got %d", result)
+	}
+}
+
+func TestMapReducePanic(t *testing.T) {
+	_, err := concurrent.MapReduce(context.Background(), 2, []int{1, 2, 3}, func(n int) (int, error) {
+		if n == 3 {
+			panic("boom")
+		}
+		return n, nil
+	}, sum)
+
+	var panicErr *concurrent.PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+		t.Errorf("Expected a *PanicError, got %v", err)
+	}
+}
+
+func TestMapReduceContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := concurrent.ParallelReduce(ctx, 2, []int{1, 2, 3}, sum)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	words := []string{"apple", "bob", "avocado", "banana", "cherry", "blueberry", "apricot"}
+
+	groups, err := concurrent.GroupBy(context.Background(), 3, words, func(word string) byte { return word[0] })
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[byte][]string{
+		'a': {"apple", "avocado", "apricot"},
+		'b': {"bob", "banana", "blueberry"},
+		'c': {"cherry"},
+	}
+	if len(groups) != len(expected) {
+		t.Errorf("Expected %d groups, got %d", len(expected), len(groups))
+	}
+	for key, values := range expected {
+		if !slices.Equal(groups[key], values) {
+			t.Errorf("Expected group %c to be %v, got %v", key, values, groups[key])
+		}
+	}
+}
+
+func TestGroupByEmpty(t *testing.T) {
+	groups, err := concurrent.GroupBy(context.Background(), 3, nil, func(n int) int { return n })
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if groups == nil || len(groups) != 0 {
+		t.Errorf("Expected an empty map, got %v", groups)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrBatchSize is returned for the items of a batch whose action returned a different number of
+// outputs than it was given inputs.
+var ErrBatchSize = errors.New("batch action returned a different number of outputs than inputs")
+
+// BatchAction is a function that transforms a batch of input items at once. It must return exactly
+// one output per input, in the same order, or an error that fails the whole batch.
+type BatchAction[Input any, Output any] func([]Input) ([]Output, error)
+
+// NewBatchStage returns a Stage that groups its input items into batches before applying the
+// action on them, which suits actions that are much cheaper in bulk. A batch is sent to the action
+// once it holds size items, or once wait has passed since its first item arrived, whichever comes
+// first; a wait of zero or less only sends partial batches when the input is exhausted. The outputs
+// are split back into items that keep the position of their inputs, so Transform and the
+// WithOrdered option preserve the input order as usual.
+//
+// The options apply to whole batches: each worker processes one batch at a time, a retry policy
+// retries the whole batch, and the weight function of WithSemaphore must take a []In. When the
+// action fails, every item of the batch fails with its error. With WithOrdered, partial batches
+// are also sent once the window is full, since no more items reach the stage until some are sent.
+func NewBatchStage[In any, Out any](size int, wait time.Duration, action BatchAction[In, Out], opts ...StageOption) Stage[In, Out] {
+	config := newStageConfig(opts)
+	size = max(size, 1)
+	batchAction := decorate(func(_ context.Context, values []In) ([]Out, error) {
+		outputs, err := action(values)
+		if err == nil && len(outputs) != len(values) {
+			return nil, fmt.Errorf("%w: got %d outputs for %d inputs", ErrBatchSize, len(outputs), len(values))
+		}
+		return outputs, err
+	}, config)
+	return Stage[In, Out]{
+		steps: 1,
+		run: func(p *pipeline, step int, in <-chan item[In]) <-chan item[Out] {
+			batches := make(chan []item[In])
+			p.goroutine(func() {
+				defer close(batches)
+				accumulate(p, size, wait, in, batches)
+			})
+			out := make(chan item[Out], config.buffer)
+			process(p, config.workers, batches,
+				func(worker, queue int, batch []item[In]) ([]Out, error) {
+					values := make([]In, len(batch))
+					for i, input := range batch {
+						values[i] = input.value
+					}
+					// The batch is reported to the observer as its first item.
+					first := item[[]In]{index: batch[0].index, value: values}
+					return observe(p, step, worker, queue, batchAction, first)
+				},
+				func(batch []item[In], outputs []Out, err error) bool {
+					for i, input := range batch {
+						if err != nil {
+							p.fail(failed(p, step, input, err))
+							continue
+						}
+						if !send(p.ctx, out, item[Out]{index: input.index, value: outputs[i]}) {
+							return false
+						}
+					}
+					return true
+				},
+				func() { close(out) },
+			)
+			return out
+		},
+	}
+}
+
+// accumulate groups the items received from in into batches of up to size items, sending each
+// batch once it is full, once wait has passed since its first item arrived, once in is closed, or,
+// in ordered runs, once the window is full.
+func accumulate[In any](p *pipeline, size int, wait time.Duration, in <-chan item[In], batches chan<- []item[In]) {
+	var batch []item[In]
+	var timer *time.Timer
+	var timeout <-chan time.Time
+	flush := func() bool {
+		if timer != nil {
+			timer.Stop()
+			timer, timeout = nil, nil
+		}
+		if len(batch) == 0 {
+			return true
+		}
+		ok := send(p.ctx, batches, batch)
+		batch = nil
+		return ok
+	}
+	defer flush()
+	for {
+		var stalled <-chan struct{}
+		if len(batch) > 0 && p.stalled != nil {
+			stalled = p.stalled()
+		}
+		select {
+		case input, ok := <-in:
+			if !ok {
+				return
+			}
+			batch = append(batch, input)
+			if len(batch) == 1 && wait > 0 {
+				timer = time.NewTimer(wait)
+				timeout = timer.C
+			}
+			if len(batch) >= size && !flush() {
+				return
+			}
+		case <-timeout:
+			if !flush() {
+				return
+			}
+		case <-stalled:
+			if !flush() {
+				return
+			}
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+// batchRecorder returns a batch action that doubles its inputs, along with a function returning the
+// sizes of the batches it received.
+func batchRecorder() (concurrent.BatchAction[int, int], func() []int) {
+	var mu sync.Mutex
+	var sizes []int
+	action := func(values []int) ([]int, error) {
+		mu.Lock()
+		sizes = append(sizes, len(values))
+		mu.Unlock()
+		outputs := make([]int, len(values))
+		for i, value := range values {
+			outputs[i] = value * 2
+		}
+		return outputs, nil
+	}
+	return action, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(sizes)
+	}
+}
+
+func TestBatchStageTransform(t *testing.T) {
+	action, sizes := batchRecorder()
+	stage := concurrent.Then(
+		concurrent.NewStage(jitter, concurrent.WithWorkers(4)),
+		concurrent.NewBatchStage(3, 0, action, concurrent.WithWorkers(2)),
+	)
+
+	items := make([]int, 10)
+	for i := range items {
+		items[i] = i
+	}
+	result, err := stage.Transform(context.Background(), items)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, output := range result {
+		if output != i*2 {
+			t.Fatalf("Expected %d at %d, got %d", i*2, i, output)
+		}
+	}
+	got := sizes()
+	slices.Sort(got)
+	if expected := []int{1, 3, 3, 3}; !slices.Equal(got, expected) {
+		t.Errorf("Expected batches of %v, got %v", expected, got)
+	}
+}
+
+func TestBatchStageWait(t *testing.T) {
+	before := runtime.NumGoroutine()
+	action, sizes := batchRecorder()
+	stage := concurrent.NewBatchStage(10, 10*time.Millisecond, action)
+
+	inputChan := make(chan int)
+	outputChan, errChan := stage.TransformChannels(context.Background(), inputChan)
+	inputChan <- 1
+	inputChan <- 2
+
+	// The batch is not full, so it must be sent once the wait is over.
+	for _, expected := range []int{2, 4} {
+		select {
+		case output := <-outputChan:
+			if output != expected {
+				t.Errorf("Expected %d, got %d", expected, output)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected the partial batch to be sent after the wait")
+		}
+	}
+	close(inputChan)
+	for range outputChan {
+	}
+	if err := <-errChan; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if got := sizes(); !slices.Equal(got, []int{2}) {
+		t.Errorf("Expected a single batch of 2, got %v", got)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestBatchStageError(t *testing.T) {
+	failure := errors.New("bulk insert failed")
+	stage := concurrent.NewBatchStage(2, 0, func(values []int) ([]int, error) {
+		if slices.Contains(values, 4) {
+			return nil, failure
+		}
+		return values, nil
+	}, concurrent.WithWorkers(1))
+
+	result, err := stage.Transform(context.Background(), []int{0, 1, 2, 3, 4, 5, 6},
+		concurrent.WithErrorPolicy(concurrent.CollectAll))
+
+	// The items reach the stage in input order, so 4 is batched with 5.
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("Expected joined errors, got %v", err)
+	}
+	var failed []int
+	for _, err := range joined.Unwrap() {
+		var itemErr *concurrent.ItemError
+		if !errors.As(err, &itemErr) || !errors.Is(err, failure) {
+			t.Fatalf("Expected an *ItemError wrapping %v, got %v", failure, err)
+		}
+		failed = append(failed, itemErr.Index)
+	}
+	if !slices.Equal(failed, []int{4, 5}) {
+		t.Errorf("Expected items 4 and 5 to fail, got %v", failed)
+	}
+	if expected := []int{0, 1, 2, 3, 0, 0, 6}; !slices.Equal(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestBatchStageSizeMismatch(t *testing.T) {
+	stage := concurrent.NewBatchStage(4, 0, func(values []int) ([]int, error) {
+		return values[1:], nil
+	})
+
+	_, err := stage.Transform(context.Background(), []int{1, 2, 3})
+
+	if !errors.Is(err, concurrent.ErrBatchSize) {
+		t.Errorf("Expected %v, got %v", concurrent.ErrBatchSize, err)
+	}
+}
+
+func TestBatchStageOrderedWithPool(t *testing.T) {
+	pool := startPool(t, 4, 4)
+	before := runtime.NumGoroutine()
+	action, _ := batchRecorder()
+	stage := concurrent.Then(
+		concurrent.NewStage(jitter, concurrent.WithWorkers(4)),
+		concurrent.NewBatchStage(4, time.Millisecond, action, concurrent.WithWorkers(3)),
+	)
+
+	outputChan, errChan := stage.TransformChannels(context.Background(), countTo(200),
+		concurrent.WithOrdered(8), concurrent.WithPool(pool))
+
+	next := 0
+	for output := range outputChan {
+		if output != next*2 {
+			t.Fatalf("Expected %d, got %d", next*2, output)
+		}
+		next++
+	}
+	if err := <-errChan; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if next != 200 {
+		t.Errorf("Expected 200 outputs, got %d", next)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestBatchStageOrderedWindowSmallerThanBatch(t *testing.T) {
+	before := runtime.NumGoroutine()
+	action, sizes := batchRecorder()
+	stage := concurrent.NewBatchStage(5, 0, action)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	outputChan, errChan := stage.TransformChannels(ctx, countTo(20), concurrent.WithOrdered(2))
+
+	next := 0
+	for output := range outputChan {
+		if output != next*2 {
+			t.Fatalf("Expected %d, got %d", next*2, output)
+		}
+		next++
+	}
+	if err := <-errChan; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if next != 20 {
+		t.Errorf("Expected 20 outputs, got %d", next)
+	}
+	for _, size := range sizes() {
+		if size > 2 {
+			t.Errorf("Expected batches no larger than the window, got %v", sizes())
+			break
+		}
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestBatchStageOrderedChained(t *testing.T) {
+	first, _ := batchRecorder()
+	second, _ := batchRecorder()
+	// Once the first stage sends a full batch, the second one holds back a partial batch whose
+	// items fill the window along with those of the first one.
+	stage := concurrent.Then(
+		concurrent.NewBatchStage(4, 0, first),
+		concurrent.NewBatchStage(3, 0, second),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	outputChan, errChan := stage.TransformChannels(ctx, countTo(50), concurrent.WithOrdered(4))
+
+	next := 0
+	for output := range outputChan {
+		if output != next*4 {
+			t.Fatalf("Expected %d, got %d", next*4, output)
+		}
+		next++
+	}
+	if err := <-errChan; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if next != 50 {
+		t.Errorf("Expected 50 outputs, got %d", next)
+	}
+}
+
+// TestBatchStageOrderedWindowStress runs many ordered runs whose window is smaller than the batch
+// size, so that slots are released while the feeder waits for one and partial batches must be sent
+// on every time the window fills up.
+func TestBatchStageOrderedWindowStress(t *testing.T) {
+	action, _ := batchRecorder()
+	jittered := func(values []int) ([]int, error) {
+		time.Sleep(time.Duration(rand.IntN(100)) * time.Microsecond)
+		return action(values)
+	}
+	stage := concurrent.Then(
+		concurrent.NewStage(jitter, concurrent.WithWorkers(2)),
+		concurrent.NewBatchStage(3, 0, jittered, concurrent.WithWorkers(2)),
+	)
+	for run := 0; run < 50; run++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		outputChan, errChan := stage.TransformChannels(ctx, countTo(30), concurrent.WithOrdered(2))
+
+		next := 0
+		for output := range outputChan {
+			if output != next*2 {
+				t.Fatalf("Run %d: expected %d, got %d", run, next*2, output)
+			}
+			next++
+		}
+		err := <-errChan
+		cancel()
+		if err != nil || next != 30 {
+			t.Fatalf("Run %d: expected 30 outputs without error, got %d and %v", run, next, err)
+		}
+	}
+}
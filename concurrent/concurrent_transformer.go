@@ -15,9 +15,17 @@
 // Package concurrent provides functionality to concurrently apply a series of transformations
 // on a list of input items while preserving the order of input items in the output. It supports
 // transformations with and without error handling and can be used with any input and output types.
+// Pipelines whose steps change the element type can be built from typed stages with NewStage and
+// Then, and the items can be aggregated concurrently with MapReduce, ParallelReduce and GroupBy.
+// Channels of items can be fanned out and in with Broadcast, Tee, Split and Merge.
 package concurrent
 
-import "sync"
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+)
 
 // Transformer is an interface that provides methods to concurrently apply a series
 // of transformations on a list of input items. It preserves the order of input items
@@ -27,216 +35,184 @@ type Transformer[Input any, Output any] interface {
 	// Transform applies the provided actions on the input items concurrently,
 	// preserving the order of input items in the output. Each action is a function
 	// that transforms an input item into an output item. This method doesn't handle
-	// errors and assumes that the actions will not return an error.
+	// errors and assumes that the actions will not return an error. If an action panics, the
+	// panic is recovered and Transform panics with a *PanicError in the calling goroutine.
 	Transform(items []Input, actions ...TransformAction[Input, Output]) []Output
 
+	// TransformContext is like Transform, but stops feeding items to the actions once ctx is
+	// done. All goroutines started by the call have exited or are exiting when it returns, and
+	// the context's error is returned if the processing did not complete.
+	TransformContext(ctx context.Context, items []Input, actions ...TransformAction[Input, Output]) ([]Output, error)
+
 	// TransformWithError applies the provided actions on the input items concurrently,
 	// preserving the order of input items in the output. Each action is a function
 	// that transforms an input item into an output item and may return an error.
 	// If an action returns an error, the processing is halted, and the error is returned.
+	// Errors are wrapped in an *ItemError, and the WithErrorPolicy option can be used to keep
+	// processing the remaining items instead. Panics in actions are recovered and reported as
+	// a *PanicError.
 	TransformWithError(items []Input, actions ...TransformActionWithError[Input, Output]) ([]Output, error)
 
+	// TransformWithErrorContext is like TransformWithError, but also halts the processing once
+	// ctx is done, returning the context's error. Whether halted by an error or by ctx, all
+	// goroutines started by the call have exited or are exiting when it returns.
+	TransformWithErrorContext(ctx context.Context, items []Input, actions ...TransformActionWithError[Input, Output]) ([]Output, error)
+
 	// TransformChannels takes a channel of input items and applies the provided actions
 	// concurrently, not guaranteeing the order of input items in the output channel. Each action
 	// is a function that transforms an input item into an output item. This method doesn't
-	// handle errors and assumes that the actions will not return an error.
+	// handle errors and assumes that the actions will not return an error. Since there is no
+	// error channel to report them on, panics in actions are not recovered.
 	TransformChannels(items <-chan Input, actions ...TransformAction[Input, Output]) <-chan Output
 
+	// TransformChannelsContext is like TransformChannels, but stops reading from the input
+	// channel once ctx is done. The output channel is closed after every goroutine started by
+	// the call has stopped sending to it.
+	TransformChannelsContext(ctx context.Context, items <-chan Input, actions ...TransformAction[Input, Output]) <-chan Output
+
 	// TransformChannelsWithError takes a channel of input items and applies the provided
 	// actions concurrently, not guaranteeing the order of input items in the output channel. Each
 	// action is a function that transforms an input item into an output item and may return
 	// an error. If an action returns an error, the processing is halted, and the error is
-	// sent to the error channel.
+	// sent to the error channel. Errors are wrapped in an *ItemError, and the WithErrorPolicy
+	// option can be used to keep processing the remaining items instead. Panics in actions are
+	// recovered and reported as a *PanicError.
 	TransformChannelsWithError(items <-chan Input, actions ...TransformActionWithError[Input, Output]) (<-chan Output, <-chan error)
+
+	// TransformChannelsWithErrorContext is like TransformChannelsWithError, but also halts the
+	// processing once ctx is done, sending the context's error to the error channel. Both
+	// channels are closed after every goroutine started by the call has stopped sending to them.
+	TransformChannelsWithErrorContext(ctx context.Context, items <-chan Input, actions ...TransformActionWithError[Input, Output]) (<-chan Output, <-chan error)
 }
 
 type transformer[Input any, Output any] struct {
 	workers int
+	opts    []Option
 }
 
 // NewTransformer returns a new Transformer instance with the specified number of workers.
-// The workers parameter determines the concurrency level of the Transformer.
-func NewTransformer[Input any, Output any](workers int) Transformer[Input, Output] {
+// The workers parameter determines the concurrency level of the Transformer, and is applied
+// uniformly to every action. Use NewStage with WithWorkers and WithBuffer to tune each step of a
+// pipeline independently. The options configure every call made through the Transformer; with
+// WithPool, the actions run on a long-lived Pool instead of goroutines started by every call. Using
+// a pool that is not running is a programming error: the methods returning an error report
+// ErrPoolClosed, Transform panics with it, and TransformChannels and TransformChannelsContext close
+// their output channel early.
+func NewTransformer[Input any, Output any](workers int, opts ...Option) Transformer[Input, Output] {
 	return &transformer[Input, Output]{
 		workers: workers,
+		opts:    opts,
 	}
 }
 
 func (t *transformer[Input, Output]) Transform(items []Input, actions ...TransformAction[Input, Output]) []Output {
-	// Send the items to the first channel along with their indices.
-	itemsCh := make(chan IndexedItem[any], len(items))
-	go func() {
-		defer close(itemsCh)
-		for i, item := range items {
-			itemsCh <- IndexedItem[any]{Index: i, Item: item}
+	transformedItems, err := t.stage(withoutErrors(actions)).Transform(context.Background(), items, t.opts...)
+	if err != nil {
+		// The actions cannot fail, so the error is either a panic in an action or a rejection by
+		// a pool that is not running, both of which are programming errors.
+		var panicErr *PanicError
+		if errors.As(err, &panicErr) {
+			panic(panicErr)
 		}
-	}()
-
-	transformedItemsCh := process[Input, Output](itemsCh, actions, t.workers, func(
-		inputChan <-chan IndexedItem[any],
-		outputChan chan<- IndexedItem[any],
-		action TransformAction[Input, Output],
-		wg *sync.WaitGroup,
-	) {
-		defer wg.Done()
-		for indexedInput := range inputChan {
-			output := action(indexedInput.Item.(Input))
-			outputChan <- IndexedItem[any]{Index: indexedInput.Index, Item: output}
-		}
-	})
-
-	// Collect the results and maintain the input order.
-	transformedItems := make([]Output, len(items))
-	for indexedItem := range transformedItemsCh {
-		transformedItems[indexedItem.Index] = indexedItem.Item.(Output)
+		panic(err)
 	}
-
 	return transformedItems
 }
 
-func (t *transformer[Input, Output]) TransformWithError(items []Input, actions ...TransformActionWithError[Input, Output]) ([]Output, error) {
-	// Send the items to the first channel along with their indices.
-	itemsCh := make(chan IndexedItemWithError[any], len(items))
-	go func() {
-		defer close(itemsCh)
-		for i, item := range items {
-			itemsCh <- IndexedItemWithError[any]{Index: i, Item: item}
-		}
-	}()
-
-	transformedItemsCh := process[Input, Output](itemsCh, actions, t.workers, func(
-		inputChan <-chan IndexedItemWithError[any],
-		outputChan chan<- IndexedItemWithError[any],
-		action TransformActionWithError[Input, Output],
-		wg *sync.WaitGroup,
-	) {
-		defer wg.Done()
-		for indexedInput := range inputChan {
-			output, err := action(indexedInput.Item.(Input))
-			outputChan <- IndexedItemWithError[any]{Index: indexedInput.Index, Item: output, Err: err}
-		}
-	})
+func (t *transformer[Input, Output]) TransformContext(ctx context.Context, items []Input, actions ...TransformAction[Input, Output]) ([]Output, error) {
+	return t.stage(withoutErrors(actions)).Transform(ctx, items, t.opts...)
+}
 
-	// Collect the results and maintain the input order.
-	transformedItems := make([]Output, len(items))
-	for indexedItem := range transformedItemsCh {
-		if indexedItem.Err != nil {
-			return nil, indexedItem.Err
-		}
-		transformedItems[indexedItem.Index] = indexedItem.Item.(Output)
-	}
+func (t *transformer[Input, Output]) TransformWithError(items []Input, actions ...TransformActionWithError[Input, Output]) ([]Output, error) {
+	return t.TransformWithErrorContext(context.Background(), items, actions...)
+}
 
-	return transformedItems, nil
+func (t *transformer[Input, Output]) TransformWithErrorContext(ctx context.Context, items []Input, actions ...TransformActionWithError[Input, Output]) ([]Output, error) {
+	return t.stage(actions).Transform(ctx, items, t.opts...)
 }
 
 func (t *transformer[Input, Output]) TransformChannels(items <-chan Input, actions ...TransformAction[Input, Output]) <-chan Output {
-	itemsCh := make(chan any, len(items))
-	go func() {
-		defer close(itemsCh)
-		for item := range items {
-			itemsCh <- item
-		}
-	}()
-
-	transformedItemsCh := process[Input, Output](itemsCh, actions, t.workers, func(
-		inputChan <-chan any,
-		outputChan chan<- any,
-		action TransformAction[Input, Output],
-		wg *sync.WaitGroup,
-	) {
-		defer wg.Done()
-		for indexedInput := range inputChan {
-			output := action(indexedInput.(Input))
-			outputChan <- output
-		}
-	})
-
-	transformedItems := make(chan Output, len(items))
-	go func() {
-		defer close(transformedItems)
-		for item := range transformedItemsCh {
-			transformedItems <- item.(Output)
-		}
-	}()
+	return t.TransformChannelsContext(context.Background(), items, actions...)
+}
 
+func (t *transformer[Input, Output]) TransformChannelsContext(ctx context.Context, items <-chan Input, actions ...TransformAction[Input, Output]) <-chan Output {
+	// The actions cannot fail, so the error channel only ever reports ctx being done, which the
+	// caller already knows about, or a rejection by a pool that is not running, which halts the
+	// run. Panics have nowhere to be reported, so they are rethrown.
+	opts := append(slices.Clip(t.opts), WithRethrowPanics())
+	transformedItems, _ := t.stage(withoutErrors(actions)).TransformChannels(ctx, items, opts...)
 	return transformedItems
 }
 
 func (t *transformer[Input, Output]) TransformChannelsWithError(items <-chan Input, actions ...TransformActionWithError[Input, Output]) (<-chan Output, <-chan error) {
-	itemsCh := make(chan ItemWithError[any], len(items))
-	go func() {
-		defer close(itemsCh)
-		for item := range items {
-			itemsCh <- ItemWithError[any]{Item: item}
+	return t.TransformChannelsWithErrorContext(context.Background(), items, actions...)
+}
+
+func (t *transformer[Input, Output]) TransformChannelsWithErrorContext(ctx context.Context, items <-chan Input, actions ...TransformActionWithError[Input, Output]) (<-chan Output, <-chan error) {
+	return t.stage(actions).TransformChannels(ctx, items, t.opts...)
+}
+
+// stage builds a pipeline that applies the actions in sequence, each one receiving the output of
+// the previous one. Since every action takes an Input, chaining more than one action is only
+// possible when Input and Output are the same type; use Then to compose stages of different
+// types.
+func (t *transformer[Input, Output]) stage(actions []TransformActionWithError[Input, Output]) Stage[Input, Output] {
+	if len(actions) == 0 {
+		panic("at least one action is required")
+	}
+	stage := NewStage(actions[0], WithWorkers(t.workers))
+	for _, action := range actions[1:] {
+		chained, ok := any(action).(TransformActionWithError[Output, Output])
+		if !ok {
+			panic("chaining multiple actions requires Input and Output to be the same type")
 		}
-	}()
+		stage = Then(stage, NewStage(chained, WithWorkers(t.workers)))
+	}
+	return stage
+}
 
-	transformedItemsCh := process[Input, Output](itemsCh, actions, t.workers, func(
-		inputChan <-chan ItemWithError[any],
-		outputChan chan<- ItemWithError[any],
-		action TransformActionWithError[Input, Output],
-		wg *sync.WaitGroup,
-	) {
-		defer wg.Done()
-		for indexedInput := range inputChan {
-			output, err := action(indexedInput.Item.(Input))
-			outputChan <- ItemWithError[any]{Item: output, Err: err}
+// withoutErrors adapts actions that cannot fail to the TransformActionWithError signature.
+func withoutErrors[Input any, Output any](actions []TransformAction[Input, Output]) []TransformActionWithError[Input, Output] {
+	adapted := make([]TransformActionWithError[Input, Output], len(actions))
+	for i, action := range actions {
+		adapted[i] = func(item Input) (Output, error) {
+			return action(item), nil
 		}
-	})
+	}
+	return adapted
+}
 
-	transformedItems := make(chan Output, len(items))
-	errors := make(chan error, 1)
-	go func() {
-		defer close(transformedItems)
-		defer close(errors)
-		for item := range transformedItemsCh {
-			if item.Err != nil {
-				errors <- item.Err
+// receive returns an iterator over the values received from ch that stops when ch is closed or
+// ctx is done, whichever happens first.
+func receive[T any](ctx context.Context, ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			select {
+			case <-ctx.Done():
 				return
+			case value, ok := <-ch:
+				if !ok || !yield(value) {
+					return
+				}
 			}
-			transformedItems <- item.Item.(Output)
 		}
-	}()
-
-	return transformedItems, errors
+	}
 }
 
-func process[
-	Input any,
-	Output any,
-	Item itemType,
-	Action actionType[Input, Output],
-	Worker func(inputChan <-chan Item, outputChan chan<- Item, action Action, wg *sync.WaitGroup),
-](
-	items <-chan Item,
-	actions []Action,
-	workers int,
-	worker Worker,
-) <-chan Item {
-	channels := make([]chan Item, len(actions))
-
-	// Create a pipeline of worker functions connected by channels.
-	for i, action := range actions {
-		var inputChan <-chan Item
-		if i == 0 {
-			inputChan = items
-		} else {
-			inputChan = channels[i-1]
-		}
-		outputChan := make(chan Item, len(items))
-		channels[i] = outputChan
-		var wg sync.WaitGroup
-		for j := 0; j < workers; j++ {
-			wg.Add(1)
-			go worker(inputChan, outputChan, action, &wg)
-		}
-		go func() {
-			wg.Wait()
-			close(outputChan)
-		}()
+// send sends value to ch unless ctx is done first. It reports whether the value was sent.
+func send[T any](ctx context.Context, ch chan<- T, value T) bool {
+	select {
+	case ch <- value:
+		return true
+	case <-ctx.Done():
+		return false
 	}
+}
 
-	return channels[len(channels)-1]
+// drain discards every value received from ch until it is closed.
+func drain[T any](ch <-chan T) {
+	for range ch {
+	}
 }
 
 // TransformAction is a function that takes an input item and transforms it into an output item.
@@ -249,8 +225,16 @@ type TransformAction[Input any, Output any] func(Input) Output
 // TransformWithError method to handle errors during the transformation process.
 type TransformActionWithError[Input any, Output any] func(Input) (Output, error)
 
+// TransformActionContext is a function that takes a context and an input item and transforms it
+// into an output item. It may return an error if the transformation fails, and should return
+// early once the context is done. This function is used with NewStageContext.
+type TransformActionContext[Input any, Output any] func(context.Context, Input) (Output, error)
+
 // ItemWithError is a struct that holds an item and an associated error. It is used to
 // represent the result of a transformation that may return an error.
+//
+// Deprecated: ItemWithError is no longer used by the Transformer, whose pipelines are now built
+// from typed stages.
 type ItemWithError[Item any] struct {
 	Item Item
 	Err  error
@@ -258,6 +242,9 @@ type ItemWithError[Item any] struct {
 
 // IndexedItem is a struct that holds an item and its index. It is used to
 // maintain the order of input items during concurrent transformations.
+//
+// Deprecated: IndexedItem is no longer used by the Transformer, whose pipelines are now built
+// from typed stages.
 type IndexedItem[Item any] struct {
 	Index int
 	Item  Item
@@ -266,16 +253,11 @@ type IndexedItem[Item any] struct {
 // IndexedItemWithError is a struct that holds an item, its index, and an associated error.
 // It is used to maintain the order of input items during concurrent transformations that
 // may return errors.
+//
+// Deprecated: IndexedItemWithError is no longer used by the Transformer, whose pipelines are now
+// built from typed stages.
 type IndexedItemWithError[Item any] struct {
 	Index int
 	Item  Item
 	Err   error
 }
-
-type itemType interface {
-	ItemWithError[any] | IndexedItem[any] | IndexedItemWithError[any] | any
-}
-
-type actionType[Input any, Output any] interface {
-	TransformAction[Input, Output] | TransformActionWithError[Input, Output]
-}
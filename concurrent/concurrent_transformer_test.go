@@ -15,10 +15,14 @@
 package concurrent_test
 
 import (
+	"context"
 	"errors"
+	"runtime"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/f0rmiga/datanalgo/concurrent"
 )
@@ -255,6 +259,186 @@ func TestTransformChannelsWithError(t *testing.T) {
 	}
 }
 
+func TestTransformChainingDifferentTypes(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic when chaining actions with different input and output types")
+		}
+	}()
+
+	transformer := concurrent.NewTransformer[string, int](2)
+	transformer.TransformWithError([]string{"a"}, length, length)
+}
+
+func TestTransformContextCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var processed atomic.Int32
+	transformer := concurrent.NewTransformer[int, int](4)
+	_, err := transformer.TransformContext(ctx, make([]int, 1000), func(item int) int {
+		if processed.Add(1) == 10 {
+			cancel()
+		}
+		return item
+	}, func(item int) int {
+		return item
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+	if n := processed.Load(); n >= 1000 {
+		t.Errorf("Expected processing to stop early, processed %d items", n)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestTransformWithErrorStopsOnFirstError(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	var processed atomic.Int32
+	transformer := concurrent.NewTransformer[int, int](4)
+	items := make([]int, 1000)
+	for i := range items {
+		items[i] = i
+	}
+	_, err := transformer.TransformWithError(items, func(item int) (int, error) {
+		processed.Add(1)
+		if item == 5 {
+			return 0, errors.New("failed")
+		}
+		time.Sleep(time.Millisecond)
+		return item, nil
+	}, func(item int) (int, error) {
+		return item, nil
+	})
+
+	var itemErr *concurrent.ItemError
+	if !errors.As(err, &itemErr) || itemErr.Index != 5 || itemErr.Err.Error() != "failed" {
+		t.Errorf("Expected action error for item 5, got: %v", err)
+	}
+	if n := processed.Load(); n >= 1000 {
+		t.Errorf("Expected processing to stop early, processed %d items", n)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestTransformWithErrorContextCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	transformer := concurrent.NewTransformer[string, string](4)
+	_, err := transformer.TransformWithErrorContext(ctx, []string{"a", "b", "c"}, upperCase)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestTransformChannelsContextCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// An input channel that is never closed.
+	inputChan := make(chan int)
+	producerDone := make(chan struct{})
+	go func() {
+		defer close(producerDone)
+		for i := 0; ; i++ {
+			select {
+			case inputChan <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	transformer := concurrent.NewTransformer[int, int](4)
+	outputChan := transformer.TransformChannelsContext(ctx, inputChan, func(item int) int {
+		return item * 2
+	})
+
+	received := 0
+	for range outputChan {
+		received++
+		if received == 10 {
+			cancel()
+		}
+	}
+
+	<-producerDone
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestTransformChannelsWithErrorContextCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	inputChan := make(chan int)
+	transformer := concurrent.NewTransformer[int, int](4)
+	outputChan, errChan := transformer.TransformChannelsWithErrorContext(ctx, inputChan, func(item int) (int, error) {
+		return item, nil
+	})
+
+	inputChan <- 1
+	<-outputChan
+	cancel()
+
+	if err := <-errChan; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+	for range outputChan {
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestTransformChannelsWithErrorNoLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	inputChan := make(chan int, 100)
+	for i := 0; i < 100; i++ {
+		inputChan <- i
+	}
+	close(inputChan)
+
+	transformer := concurrent.NewTransformer[int, int](4)
+	_, errChan := transformer.TransformChannelsWithError(inputChan, func(item int) (int, error) {
+		if item == 3 {
+			return 0, errors.New("failed")
+		}
+		return item, nil
+	})
+
+	// Only the error channel is read; the output channel is abandoned.
+	if err := <-errChan; err == nil {
+		t.Error("Expected an error but did not receive one")
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+// assertNoGoroutineLeak waits for the number of goroutines to drop back to at most before,
+// failing the test if it does not happen within a second.
+func assertNoGoroutineLeak(t *testing.T, before int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		after := runtime.NumGoroutine()
+		if after <= before {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Errorf("Goroutine leak: %d goroutines before, %d after", before, after)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 func runTest[Input any, Output comparable](tc testCase[Input, Output]) func(t *testing.T) {
 	return func(t *testing.T) {
 		transformer := concurrent.NewTransformer[Input, Output](tc.workers)
@@ -0,0 +1,41 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import "fmt"
+
+// ItemError describes the failure of a single item in a pipeline.
+type ItemError struct {
+	// Index is the position of the failed item in the input.
+	Index int
+	// Stage is the position of the stage that failed, counting the stages created with NewStage
+	// from zero.
+	Stage int
+	// Input is the original input item, before any stage was applied. It is nil for failures past
+	// the first stage of TransformChannels, where the input item is no longer kept around.
+	Input any
+	// Err is the error returned by the stage.
+	Err error
+}
+
+// Error returns a string representation of the error.
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d failed at stage %d: %v", e.Index, e.Stage, e.Err)
+}
+
+// Unwrap returns the error returned by the stage.
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
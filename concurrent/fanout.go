@@ -0,0 +1,172 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import (
+	"context"
+	"sync"
+)
+
+// SlowConsumerPolicy determines how Broadcast handles consumers that are not ready to receive an
+// item.
+type SlowConsumerPolicy int
+
+const (
+	// Block waits for every consumer to receive each item before sending the next one, so the
+	// slowest consumer sets the pace of all of them. This is the default policy.
+	Block SlowConsumerPolicy = iota
+
+	// Drop holds up to one unread item per consumer, and drops the items for the consumers whose
+	// previous item is still unread, so a slow consumer never holds up the rest.
+	Drop
+
+	// Buffer queues the items for each consumer without bounds, so every consumer receives every
+	// item at its own pace. A consumer that falls behind makes its queue, and the memory used,
+	// grow.
+	Buffer
+)
+
+// Merge sends the items received from every channel to the returned channel, in the order they
+// are received. The returned channel is closed once every input channel is closed, or once ctx is
+// done.
+func Merge[T any](ctx context.Context, channels ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(channels))
+	for _, ch := range channels {
+		go func() {
+			defer wg.Done()
+			for value := range receive(ctx, ch) {
+				if !send(ctx, out, value) {
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Broadcast sends every item received from the input channel to each of the given number of
+// consumers, handling the consumers that are not ready according to the policy. The returned
+// channels are closed once the input channel is closed and, with the Buffer policy, their queues
+// are drained, or once ctx is done.
+func Broadcast[T any](ctx context.Context, items <-chan T, consumers int, policy SlowConsumerPolicy) []<-chan T {
+	outs := make([]chan T, consumers)
+	results := make([]<-chan T, consumers)
+	for i := range outs {
+		if policy == Drop {
+			outs[i] = make(chan T, 1)
+		} else {
+			outs[i] = make(chan T)
+		}
+		results[i] = outs[i]
+	}
+
+	targets := outs
+	if policy == Buffer {
+		// Every consumer gets a queue that is always ready to receive.
+		targets = make([]chan T, consumers)
+		for i := range targets {
+			targets[i] = make(chan T)
+			go func() {
+				defer close(outs[i])
+				enqueue(ctx, targets[i], outs[i])
+			}()
+		}
+	}
+
+	go func() {
+		defer func() {
+			for _, target := range targets {
+				close(target)
+			}
+		}()
+		for value := range receive(ctx, items) {
+			for _, target := range targets {
+				if policy == Drop {
+					select {
+					case target <- value:
+					default:
+					}
+				} else if !send(ctx, target, value) {
+					return
+				}
+			}
+		}
+	}()
+	return results
+}
+
+// Tee sends every item received from the input channel to both returned channels, like Broadcast
+// with two consumers and the Block policy, which allows observing a stream from a side channel.
+func Tee[T any](ctx context.Context, items <-chan T) (<-chan T, <-chan T) {
+	outs := Broadcast(ctx, items, 2, Block)
+	return outs[0], outs[1]
+}
+
+// Split sends the items received from the input channel that satisfy pred to the first returned
+// channel, and the others to the second one. Both channels must be read, since an item waits for
+// its channel to receive it before the next item is read. The channels are closed once the input
+// channel is closed, or once ctx is done.
+func Split[T any](ctx context.Context, items <-chan T, pred func(T) bool) (<-chan T, <-chan T) {
+	matched := make(chan T)
+	unmatched := make(chan T)
+	go func() {
+		defer close(matched)
+		defer close(unmatched)
+		for value := range receive(ctx, items) {
+			out := unmatched
+			if pred(value) {
+				out = matched
+			}
+			if !send(ctx, out, value) {
+				return
+			}
+		}
+	}()
+	return matched, unmatched
+}
+
+// enqueue forwards the items received from in to out through an unbounded queue, so that sending
+// to in never waits for out to be read. It returns once in is closed and the queue is drained, or
+// once ctx is done.
+func enqueue[T any](ctx context.Context, in <-chan T, out chan<- T) {
+	var queue []T
+	for in != nil || len(queue) > 0 {
+		var next chan<- T
+		var head T
+		if len(queue) > 0 {
+			next, head = out, queue[0]
+		}
+		select {
+		case value, ok := <-in:
+			if !ok {
+				in = nil
+				continue
+			}
+			queue = append(queue, value)
+		case next <- head:
+			var zero T
+			queue[0] = zero
+			queue = queue[1:]
+		case <-ctx.Done():
+			return
+		}
+	}
+}
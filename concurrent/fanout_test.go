@@ -0,0 +1,188 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+// collect returns every item received from the channel until it is closed.
+func collect[T any](ch <-chan T) []T {
+	var items []T
+	for item := range ch {
+		items = append(items, item)
+	}
+	return items
+}
+
+// collectAll collects the channels concurrently and returns their items in the same order.
+func collectAll[T any](channels []<-chan T) [][]T {
+	results := make([][]T, len(channels))
+	var wg sync.WaitGroup
+	for i, ch := range channels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = collect(ch)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func TestMerge(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	merged := collect(concurrent.Merge(context.Background(), countTo(10), countTo(5), countTo(0)))
+
+	slices.Sort(merged)
+	expected := []int{0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 5, 6, 7, 8, 9}
+	if !slices.Equal(merged, expected) {
+		t.Errorf("Expected %v, got %v", expected, merged)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestMergeContextCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Input channels that are never closed; the cancellation must stop the reading.
+	merged := concurrent.Merge(ctx, make(chan int), make(chan int))
+	cancel()
+
+	if items := collect(merged); len(items) != 0 {
+		t.Errorf("Expected no items, got %v", items)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestBroadcastBlock(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	outs := concurrent.Broadcast(context.Background(), countTo(100), 3, concurrent.Block)
+
+	for i, items := range collectAll(outs) {
+		if len(items) != 100 || !slices.IsSorted(items) {
+			t.Errorf("Expected consumer %d to receive the 100 items in order, got %v", i, items)
+		}
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestBroadcastDrop(t *testing.T) {
+	before := runtime.NumGoroutine()
+	items := make(chan int)
+
+	outs := concurrent.Broadcast(context.Background(), items, 2, concurrent.Drop)
+
+	// The second consumer only reads at the end, so it must not hold up the first one.
+	for i := 0; i < 10; i++ {
+		items <- i
+		select {
+		case value := <-outs[0]:
+			if value != i {
+				t.Errorf("Expected %d, got %d", i, value)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected the fast consumer to receive %d", i)
+		}
+	}
+	close(items)
+	collect(outs[0])
+	if kept := collect(outs[1]); !slices.Equal(kept, []int{0}) {
+		t.Errorf("Expected the slow consumer to only keep the first item, got %v", kept)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestBroadcastBuffer(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	outs := concurrent.Broadcast(context.Background(), countTo(100), 2, concurrent.Buffer)
+
+	// The first consumer reads everything before the second one starts.
+	fast := collect(outs[0])
+	slow := collect(outs[1])
+	if len(fast) != 100 || !slices.Equal(fast, slow) {
+		t.Errorf("Expected both consumers to receive the 100 items, got %d and %d", len(fast), len(slow))
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestBroadcastContextCancel(t *testing.T) {
+	for _, policy := range []concurrent.SlowConsumerPolicy{concurrent.Block, concurrent.Drop, concurrent.Buffer} {
+		before := runtime.NumGoroutine()
+		ctx, cancel := context.WithCancel(context.Background())
+		items := make(chan int)
+
+		outs := concurrent.Broadcast(ctx, items, 2, policy)
+		cancel()
+
+		collectAll(outs)
+		assertNoGoroutineLeak(t, before)
+	}
+}
+
+func TestTee(t *testing.T) {
+	main, side := concurrent.Tee(context.Background(), countTo(10))
+
+	results := collectAll([]<-chan int{main, side})
+
+	if !slices.Equal(results[0], results[1]) || len(results[0]) != 10 {
+		t.Errorf("Expected both channels to receive the 10 items, got %v and %v", results[0], results[1])
+	}
+}
+
+func TestSplit(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	even, odd := concurrent.Split(context.Background(), countTo(10), func(n int) bool { return n%2 == 0 })
+
+	results := collectAll([]<-chan int{even, odd})
+	if !slices.Equal(results[0], []int{0, 2, 4, 6, 8}) || !slices.Equal(results[1], []int{1, 3, 5, 7, 9}) {
+		t.Errorf("Expected the even and odd items, got %v and %v", results[0], results[1])
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestFanOutFanInWithStages(t *testing.T) {
+	ctx := context.Background()
+	double := concurrent.NewStage(func(n int) (int, error) { return n * 2, nil }, concurrent.WithWorkers(2))
+	negate := concurrent.NewStage(func(n int) (int, error) { return -n, nil }, concurrent.WithWorkers(2))
+
+	small, large := concurrent.Split(ctx, countTo(10), func(n int) bool { return n < 5 })
+	doubled, doubleErrs := double.TransformChannels(ctx, small)
+	negated, negateErrs := negate.TransformChannels(ctx, large)
+	merged := collect(concurrent.Merge(ctx, doubled, negated))
+
+	for _, errs := range []<-chan error{doubleErrs, negateErrs} {
+		if err := <-errs; err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	slices.Sort(merged)
+	expected := []int{-9, -8, -7, -6, -5, 0, 2, 4, 6, 8}
+	if !slices.Equal(merged, expected) {
+		t.Errorf("Expected %v, got %v", expected, merged)
+	}
+}
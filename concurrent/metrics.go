@@ -0,0 +1,168 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+)
+
+// latencySamples is the number of latencies kept per stage to compute percentiles.
+const latencySamples = 4096
+
+// Metrics is an Observer that aggregates the events of one or more runs in memory, per stage. The
+// zero value is ready to use, and its methods are safe for concurrent use.
+type Metrics struct {
+	mu     sync.Mutex
+	stages map[int]*stageMetrics
+}
+
+var _ Observer = (*Metrics)(nil)
+
+// StageMetrics is a snapshot of the metrics of a stage.
+type StageMetrics struct {
+	// Stage is the position of the stage, counting the stages created with NewStage from zero.
+	Stage int
+	// Finished is the number of items the stage processed successfully.
+	Finished int
+	// Failed is the number of items the stage failed to process.
+	Failed int
+	// Throughput is the number of items, finished or failed, processed per second, measured from
+	// the first item started to the last item done.
+	Throughput float64
+	// P50 and P99 are the median and 99th percentile of the latency of the action. Once more than
+	// 4096 items were processed, they are estimated from a uniform sample of the latencies.
+	P50, P99 time.Duration
+	// Utilization is the fraction, between 0 and 1, of the time its workers spent running the
+	// action, measured over the same period as the throughput.
+	Utilization float64
+	// MaxQueueDepth is the largest number of items seen waiting in the input buffer of the stage.
+	MaxQueueDepth int
+}
+
+type stageMetrics struct {
+	finished, failed int
+	first, last      time.Time
+	busy             time.Duration
+	workers          int
+	maxQueueDepth    int
+	latencies        []time.Duration
+}
+
+// ItemStarted implements Observer.
+func (m *Metrics) ItemStarted(event Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stage := m.stage(event.Stage)
+	if stage.first.IsZero() || event.Time.Before(stage.first) {
+		stage.first = event.Time
+	}
+	stage.workers = max(stage.workers, event.Worker+1)
+	stage.maxQueueDepth = max(stage.maxQueueDepth, event.QueueDepth)
+}
+
+// ItemFinished implements Observer.
+func (m *Metrics) ItemFinished(event Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stage := m.stage(event.Stage)
+	stage.finished++
+	stage.done(event)
+}
+
+// ItemFailed implements Observer.
+func (m *Metrics) ItemFailed(event Event, _ error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stage := m.stage(event.Stage)
+	stage.failed++
+	stage.done(event)
+}
+
+// Stages returns a snapshot of the metrics of every stage that processed an item, ordered by
+// stage.
+func (m *Metrics) Stages() []StageMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make([]StageMetrics, 0, len(m.stages))
+	for index, stage := range m.stages {
+		metrics := StageMetrics{
+			Stage:         index,
+			Finished:      stage.finished,
+			Failed:        stage.failed,
+			MaxQueueDepth: stage.maxQueueDepth,
+		}
+		if elapsed := stage.last.Sub(stage.first); elapsed > 0 {
+			metrics.Throughput = float64(stage.finished+stage.failed) / elapsed.Seconds()
+			metrics.Utilization = min(float64(stage.busy)/(float64(elapsed)*float64(stage.workers)), 1)
+		}
+		if len(stage.latencies) > 0 {
+			latencies := slices.Clone(stage.latencies)
+			slices.Sort(latencies)
+			metrics.P50 = percentile(latencies, 0.50)
+			metrics.P99 = percentile(latencies, 0.99)
+		}
+		snapshot = append(snapshot, metrics)
+	}
+	slices.SortFunc(snapshot, func(a, b StageMetrics) int {
+		return a.Stage - b.Stage
+	})
+	return snapshot
+}
+
+// Reset discards every metric collected so far.
+func (m *Metrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stages = nil
+}
+
+// stage returns the metrics of the stage, creating them if needed. It must be called with the
+// lock held.
+func (m *Metrics) stage(index int) *stageMetrics {
+	if m.stages == nil {
+		m.stages = make(map[int]*stageMetrics)
+	}
+	stage, ok := m.stages[index]
+	if !ok {
+		stage = &stageMetrics{}
+		m.stages[index] = stage
+	}
+	return stage
+}
+
+// done records an item that the stage finished processing, keeping a uniform sample of the
+// latencies with reservoir sampling.
+func (stage *stageMetrics) done(event Event) {
+	if end := event.Time.Add(event.Latency); end.After(stage.last) {
+		stage.last = end
+	}
+	stage.busy += event.Latency
+	stage.workers = max(stage.workers, event.Worker+1)
+	if len(stage.latencies) < latencySamples {
+		stage.latencies = append(stage.latencies, event.Latency)
+	} else if i := rand.IntN(stage.finished + stage.failed); i < latencySamples {
+		stage.latencies[i] = event.Latency
+	}
+}
+
+// percentile returns the p-th percentile of the sorted latencies, using the nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
@@ -0,0 +1,108 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+func TestMetrics(t *testing.T) {
+	var metrics concurrent.Metrics
+	start := time.Now()
+	// Two workers, each busy for 100ms of a 200ms period, processing 100 items of 1ms to 100ms.
+	for i := 1; i <= 100; i++ {
+		worker := i % 2
+		event := concurrent.Event{
+			Stage:   0,
+			Worker:  worker,
+			Index:   i,
+			Time:    start.Add(time.Duration(i) * time.Millisecond),
+			Latency: time.Duration(i) * time.Millisecond / 50,
+		}
+		metrics.ItemStarted(event)
+		if i%10 == 0 {
+			metrics.ItemFailed(event, errors.New("failed"))
+		} else {
+			metrics.ItemFinished(event)
+		}
+	}
+
+	stages := metrics.Stages()
+
+	if len(stages) != 1 {
+		t.Fatalf("Expected 1 stage, got %d", len(stages))
+	}
+	stage := stages[0]
+	if stage.Finished != 90 || stage.Failed != 10 {
+		t.Errorf("Expected 90 finished and 10 failed, got %d and %d", stage.Finished, stage.Failed)
+	}
+	if stage.P50 != 1000*time.Microsecond || stage.P99 != 1980*time.Microsecond {
+		t.Errorf("Expected p50 of 1ms and p99 of 1.98ms, got %v and %v", stage.P50, stage.P99)
+	}
+	// The items span from 1ms to 102ms after the start.
+	elapsed := 101 * time.Millisecond
+	if want := 100 / elapsed.Seconds(); math.Abs(stage.Throughput-want) > 0.01 {
+		t.Errorf("Expected a throughput of %.2f items/s, got %.2f", want, stage.Throughput)
+	}
+	// The latencies add up to 101ms of work over two workers.
+	if want := 0.5; math.Abs(stage.Utilization-want) > 0.01 {
+		t.Errorf("Expected a utilization of %.2f, got %.2f", want, stage.Utilization)
+	}
+
+	metrics.Reset()
+	if stages := metrics.Stages(); len(stages) != 0 {
+		t.Errorf("Expected no stages after Reset, got %v", stages)
+	}
+}
+
+func TestMetricsBottleneck(t *testing.T) {
+	var metrics concurrent.Metrics
+	fast := func(n int) (int, error) { return n, nil }
+	slow := func(n int) (int, error) {
+		time.Sleep(2 * time.Millisecond)
+		return n, nil
+	}
+	stage := concurrent.Then(
+		concurrent.NewStage(fast, concurrent.WithWorkers(2)),
+		concurrent.NewStage(slow, concurrent.WithWorkers(2)),
+	)
+
+	_, err := stage.Transform(context.Background(), make([]int, 50), concurrent.WithObserver(&metrics))
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stages := metrics.Stages()
+	if len(stages) != 2 {
+		t.Fatalf("Expected 2 stages, got %d", len(stages))
+	}
+	for i, stage := range stages {
+		if stage.Stage != i || stage.Finished != 50 {
+			t.Errorf("Expected stage %d to finish 50 items, got stage %d with %d", i, stage.Stage, stage.Finished)
+		}
+	}
+	if stages[1].P50 < 2*time.Millisecond {
+		t.Errorf("Expected the slow stage's p50 to be at least 2ms, got %v", stages[1].P50)
+	}
+	if stages[1].Utilization <= stages[0].Utilization {
+		t.Errorf("Expected the slow stage to be busier, got %.2f and %.2f", stages[0].Utilization, stages[1].Utilization)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import "time"
+
+// Observer receives an event every time a worker of a stage processes an item, which allows
+// tracing a pipeline and finding its bottlenecks. Its methods are called synchronously from the
+// workers, concurrently, so they must be safe for concurrent use and return quickly. Metrics is a
+// built-in Observer that aggregates the events in memory.
+type Observer interface {
+	// ItemStarted is called right before the action of a stage is applied on an item.
+	ItemStarted(event Event)
+	// ItemFinished is called after the action of a stage succeeded on an item.
+	ItemFinished(event Event)
+	// ItemFailed is called after the action of a stage failed on an item, with the error it
+	// returned. With a retry policy, the action only fails once the retries are exhausted.
+	ItemFailed(event Event, err error)
+}
+
+// Event describes an item processed by a worker of a stage.
+type Event struct {
+	// Stage is the position of the stage, counting the stages created with NewStage from zero.
+	Stage int
+	// Worker identifies the worker of the stage that processes the item, from zero to the number
+	// of workers of the stage minus one.
+	Worker int
+	// Index is the position of the item in the input.
+	Index int
+	// Time is the time at which the worker started processing the item.
+	Time time.Time
+	// Latency is the time the action took. It is zero for ItemStarted.
+	Latency time.Duration
+	// QueueDepth is the number of items waiting in the input buffer of the stage when the worker
+	// picked up the item. It is always zero for stages that follow a stage without WithBuffer.
+	QueueDepth int
+}
+
+// WithObserver makes the run report the processing of every item to the observer.
+func WithObserver(observer Observer) Option {
+	return func(config *config) {
+		config.observer = observer
+	}
+}
+
+// observe calls the action on the input item like protect does, reporting it to the observer of
+// the run, if any.
+func observe[In any, Out any](p *pipeline, step, worker, queue int, action TransformActionContext[In, Out], input item[In]) (Out, error) {
+	observer := p.config.observer
+	if observer == nil {
+		return protect(p, step, action, input)
+	}
+	event := Event{Stage: step, Worker: worker, Index: input.index, Time: time.Now(), QueueDepth: queue}
+	observer.ItemStarted(event)
+	output, err := protect(p, step, action, input)
+	event.Latency = time.Since(event.Time)
+	if err != nil {
+		observer.ItemFailed(event, err)
+	} else {
+		observer.ItemFinished(event)
+	}
+	return output, err
+}
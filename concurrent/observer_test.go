@@ -0,0 +1,154 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+// recorder is an Observer that records every event it receives.
+type recorder struct {
+	mu       sync.Mutex
+	started  []concurrent.Event
+	finished []concurrent.Event
+	failed   []concurrent.Event
+	errs     []error
+}
+
+func (r *recorder) ItemStarted(event concurrent.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.started = append(r.started, event)
+}
+
+func (r *recorder) ItemFinished(event concurrent.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished = append(r.finished, event)
+}
+
+func (r *recorder) ItemFailed(event concurrent.Event, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed = append(r.failed, event)
+	r.errs = append(r.errs, err)
+}
+
+func TestObserver(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []concurrent.Option
+	}{
+		{name: "goroutines"},
+		{name: "pool", opts: []concurrent.Option{concurrent.WithPool(startPool(t, 4, 4))}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var r recorder
+			sleepy := func(n int) (int, error) {
+				time.Sleep(time.Millisecond)
+				return n, nil
+			}
+			stage := concurrent.Then(
+				concurrent.NewStage(sleepy, concurrent.WithWorkers(3)),
+				concurrent.NewStage(failOnOdd, concurrent.WithWorkers(2)),
+			)
+			opts := append(tc.opts, concurrent.WithObserver(&r), concurrent.WithErrorPolicy(concurrent.Skip))
+
+			_, err := stage.Transform(context.Background(), []int{0, 1, 2, 3, 4, 5}, opts...)
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(r.started) != 12 || len(r.finished) != 9 || len(r.failed) != 3 {
+				t.Fatalf("Expected 12 started, 9 finished and 3 failed, got %d, %d and %d",
+					len(r.started), len(r.finished), len(r.failed))
+			}
+			workers := []int{3, 2}
+			for _, event := range r.started {
+				if event.Worker < 0 || event.Worker >= workers[event.Stage] {
+					t.Errorf("Unexpected worker %d in stage %d", event.Worker, event.Stage)
+				}
+				if event.Latency != 0 {
+					t.Errorf("Expected no latency when started, got %v", event.Latency)
+				}
+			}
+			for _, event := range r.finished {
+				if event.Stage == 0 && event.Latency < time.Millisecond {
+					t.Errorf("Expected a latency of at least 1ms, got %v", event.Latency)
+				}
+			}
+			for i, event := range r.failed {
+				if event.Stage != 1 || event.Index%2 != 1 || !errors.Is(r.errs[i], errOdd) {
+					t.Errorf("Unexpected failure of item %d at stage %d: %v", event.Index, event.Stage, r.errs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestObserverQueueDepth(t *testing.T) {
+	var r recorder
+	release := make(chan struct{})
+	stage := concurrent.Then(
+		concurrent.NewStage(func(n int) (int, error) { return n, nil }, concurrent.WithBuffer(8)),
+		concurrent.NewStage(func(n int) (int, error) {
+			if n == 0 {
+				<-release
+			}
+			return n, nil
+		}, concurrent.WithWorkers(1)),
+	)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	_, err := stage.Transform(context.Background(), make([]int, 9), concurrent.WithObserver(&r))
+	<-done
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	depth := 0
+	for _, event := range r.started {
+		if event.Stage == 1 {
+			depth = max(depth, event.QueueDepth)
+		}
+	}
+	if depth == 0 {
+		t.Errorf("Expected items to queue up in front of the blocked stage")
+	}
+}
+
+// startPool returns a running pool that is shut down when the test finishes.
+func startPool(t *testing.T, workers, queue int) *concurrent.Pool {
+	t.Helper()
+	pool := concurrent.NewPool(workers, queue)
+	pool.Start()
+	t.Cleanup(func() {
+		if err := pool.Shutdown(context.Background()); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+	return pool
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import "runtime"
+
+// Option configures a run of a pipeline. Options are passed to NewTransformer or to the methods
+// that run a Stage, and apply to every stage of the pipeline.
+type Option func(*config)
+
+type config struct {
+	errorPolicy   ErrorPolicy
+	rethrowPanics bool
+	window        int
+	pool          *Pool
+	observer      Observer
+}
+
+func newConfig(opts []Option) config {
+	var config config
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// ErrorPolicy determines how a run reacts to items that fail in any of its stages.
+type ErrorPolicy int
+
+const (
+	// FailFast halts the processing on the first failed item and reports only that failure. This
+	// is the default policy.
+	FailFast ErrorPolicy = iota
+
+	// CollectAll keeps processing the remaining items after a failure and reports every failure.
+	// Transform returns the partial outputs, with the zero value in place of each failed item,
+	// along with the errors.Join of all failures ordered by input index.
+	CollectAll
+
+	// Skip keeps processing the remaining items after a failure and drops the failed items
+	// without reporting them. Transform returns only the outputs of the items that succeeded, in
+	// input order. A panic in an action is not dropped: it halts the run as under FailFast.
+	Skip
+)
+
+// WithErrorPolicy sets how the run reacts to failed items. The default is FailFast.
+func WithErrorPolicy(policy ErrorPolicy) Option {
+	return func(config *config) {
+		config.errorPolicy = policy
+	}
+}
+
+// StageOption configures a single stage of a pipeline. Options are passed to NewStage, so that
+// every stage of a pipeline can be tuned independently.
+type StageOption func(*stageConfig)
+
+type stageConfig struct {
+	workers   int
+	buffer    int
+	retry     *RetryPolicy
+	limiter   *RateLimiter
+	semaphore *Semaphore
+	// weight is the func(In) int64 passed to WithSemaphore, checked against the input type of
+	// the stage by NewStage.
+	weight any
+}
+
+func newStageConfig(opts []StageOption) stageConfig {
+	config := stageConfig{
+		workers: runtime.GOMAXPROCS(0),
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	config.workers = max(config.workers, 1)
+	config.buffer = max(config.buffer, 0)
+	return config
+}
+
+// WithWorkers sets the number of workers that concurrently apply the stage's action. At least one
+// worker is always started. The default is runtime.GOMAXPROCS(0), which suits CPU-bound actions;
+// I/O-bound actions usually benefit from more workers.
+func WithWorkers(workers int) StageOption {
+	return func(config *stageConfig) {
+		config.workers = workers
+	}
+}
+
+// WithBuffer sets the capacity of the channel the stage sends its output items to, which lets the
+// stage get ahead of the next one by up to that many items. The default is an unbuffered channel.
+func WithBuffer(size int) StageOption {
+	return func(config *stageConfig) {
+		config.buffer = size
+	}
+}
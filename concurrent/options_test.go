@@ -0,0 +1,280 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+func TestWithWorkersPerStage(t *testing.T) {
+	fetch := concurrencyTracker{}
+	compute := concurrencyTracker{}
+	stage := concurrent.Then(
+		concurrent.NewStage(fetch.track, concurrent.WithWorkers(8)),
+		concurrent.NewStage(compute.track, concurrent.WithWorkers(2)),
+	)
+
+	result, err := stage.Transform(context.Background(), make([]int, 32))
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result) != 32 {
+		t.Errorf("Unexpected result length: %d", len(result))
+	}
+	if peak := fetch.peak.Load(); peak > 8 || peak < 3 {
+		t.Errorf("Expected first stage to run up to 8 workers, peak was %d", peak)
+	}
+	if peak := compute.peak.Load(); peak > 2 {
+		t.Errorf("Expected second stage to run at most 2 workers, peak was %d", peak)
+	}
+}
+
+func TestWithBuffer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var processed atomic.Int32
+	stage := concurrent.NewStage(func(n int) (int, error) {
+		processed.Add(1)
+		return n, nil
+	}, concurrent.WithWorkers(1), concurrent.WithBuffer(3))
+
+	inputChan := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		inputChan <- i
+	}
+	close(inputChan)
+
+	// Nothing reads the output channel, so the stage can only get ahead by the size of its
+	// buffer, plus the item held by the worker and the one held by the output forwarder.
+	outputChan, _ := stage.TransformChannels(ctx, inputChan)
+	time.Sleep(50 * time.Millisecond)
+
+	if n := processed.Load(); n != 5 {
+		t.Errorf("Expected 5 processed items, got %d", n)
+	}
+
+	received := 0
+	for range outputChan {
+		received++
+	}
+	if received != 10 {
+		t.Errorf("Expected 10 items, got %d", received)
+	}
+}
+
+func TestErrorPolicyFailFast(t *testing.T) {
+	stage := concurrent.Then(
+		concurrent.NewStage(length, concurrent.WithWorkers(2)),
+		concurrent.NewStage(failOnOdd, concurrent.WithWorkers(2)),
+	)
+
+	result, err := stage.Transform(context.Background(), []string{"aa", "bbb", "cccc"})
+
+	var itemErr *concurrent.ItemError
+	if !errors.As(err, &itemErr) {
+		t.Fatalf("Expected *ItemError, got: %v", err)
+	}
+	if itemErr.Index != 1 || itemErr.Stage != 1 || itemErr.Input != "bbb" || !errors.Is(err, errOdd) {
+		t.Errorf("Unexpected item error: %+v", itemErr)
+	}
+	if result != nil {
+		t.Errorf("Expected nil result, got %v", result)
+	}
+}
+
+func TestErrorPolicyFailFastChannels(t *testing.T) {
+	stage := concurrent.Then(
+		concurrent.NewStage(length, concurrent.WithWorkers(2)),
+		concurrent.NewStage(failOnOdd, concurrent.WithWorkers(2)),
+	)
+	inputChan := make(chan string, 3)
+	inputChan <- "aa"
+	inputChan <- "bbb"
+	inputChan <- "cccc"
+	close(inputChan)
+
+	outputChan, errChan := stage.TransformChannels(context.Background(), inputChan)
+	for range outputChan {
+	}
+
+	var itemErr *concurrent.ItemError
+	if err := <-errChan; !errors.As(err, &itemErr) {
+		t.Fatalf("Expected *ItemError, got: %v", err)
+	}
+	// The input item is not kept around past the first stage of a channel run.
+	if itemErr.Index != 1 || itemErr.Stage != 1 || itemErr.Input != nil || !errors.Is(itemErr, errOdd) {
+		t.Errorf("Unexpected item error: %+v", itemErr)
+	}
+}
+
+func TestErrorPolicyCollectAll(t *testing.T) {
+	stage := concurrent.Then(
+		concurrent.NewStage(length, concurrent.WithWorkers(2)),
+		concurrent.NewStage(failOnOdd, concurrent.WithWorkers(2)),
+	)
+	input := []string{"a", "bb", "ccc", "dddd", "eeeee"}
+
+	result, err := stage.Transform(context.Background(), input, concurrent.WithErrorPolicy(concurrent.CollectAll))
+
+	expected := []int{0, 2, 0, 4, 0}
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, result)
+			break
+		}
+	}
+
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) {
+		t.Fatalf("Expected joined errors, got: %v", err)
+	}
+	failures := joined.Unwrap()
+	if len(failures) != 3 {
+		t.Fatalf("Expected 3 failures, got %d: %v", len(failures), err)
+	}
+	for i, index := range []int{0, 2, 4} {
+		var itemErr *concurrent.ItemError
+		if !errors.As(failures[i], &itemErr) || itemErr.Index != index || itemErr.Input != input[index] {
+			t.Errorf("Unexpected failure %d: %v", i, failures[i])
+		}
+	}
+}
+
+func TestErrorPolicySkip(t *testing.T) {
+	transformer := concurrent.NewTransformer[int, int](3, concurrent.WithErrorPolicy(concurrent.Skip))
+
+	result, err := transformer.TransformWithError([]int{1, 2, 3, 4, 5, 6}, failOnOdd)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(result) != 3 || result[0] != 2 || result[1] != 4 || result[2] != 6 {
+		t.Errorf("Expected [2 4 6], got %v", result)
+	}
+}
+
+func TestErrorPolicyCollectAllChannels(t *testing.T) {
+	transformer := concurrent.NewTransformer[int, int](3, concurrent.WithErrorPolicy(concurrent.CollectAll))
+	inputChan := make(chan int, 6)
+	for i := 1; i <= 6; i++ {
+		inputChan <- i
+	}
+	close(inputChan)
+
+	outputChan, errChan := transformer.TransformChannelsWithError(inputChan, failOnOdd)
+
+	var outputs []int
+	var failed []string
+	for outputChan != nil || errChan != nil {
+		select {
+		case output, ok := <-outputChan:
+			if !ok {
+				outputChan = nil
+				continue
+			}
+			outputs = append(outputs, output)
+		case err, ok := <-errChan:
+			if !ok {
+				errChan = nil
+				continue
+			}
+			var itemErr *concurrent.ItemError
+			if !errors.As(err, &itemErr) {
+				t.Fatalf("Expected *ItemError, got: %v", err)
+			}
+			failed = append(failed, itemErr.Error())
+		}
+	}
+
+	sort.Ints(outputs)
+	sort.Strings(failed)
+	if len(outputs) != 3 || outputs[0] != 2 || outputs[1] != 4 || outputs[2] != 6 {
+		t.Errorf("Expected [2 4 6], got %v", outputs)
+	}
+	expectedFailures := []string{
+		"item 0 failed at stage 0: odd value",
+		"item 2 failed at stage 0: odd value",
+		"item 4 failed at stage 0: odd value",
+	}
+	if strings.Join(failed, "\n") != strings.Join(expectedFailures, "\n") {
+		t.Errorf("Unexpected failures: %v", failed)
+	}
+}
+
+func TestErrorPolicySkipChannels(t *testing.T) {
+	stage := concurrent.NewStage(failOnOdd, concurrent.WithWorkers(3))
+	inputChan := make(chan int, 6)
+	for i := 1; i <= 6; i++ {
+		inputChan <- i
+	}
+	close(inputChan)
+
+	outputChan, errChan := stage.TransformChannels(context.Background(), inputChan, concurrent.WithErrorPolicy(concurrent.Skip))
+
+	var outputs []int
+	for output := range outputChan {
+		outputs = append(outputs, output)
+	}
+	if err := <-errChan; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	sort.Ints(outputs)
+	if len(outputs) != 3 || outputs[0] != 2 || outputs[1] != 4 || outputs[2] != 6 {
+		t.Errorf("Expected [2 4 6], got %v", outputs)
+	}
+}
+
+var errOdd = errors.New("odd value")
+
+// failOnOdd returns an error for odd values and the value itself otherwise.
+func failOnOdd(n int) (int, error) {
+	if n%2 == 1 {
+		return 0, errOdd
+	}
+	return n, nil
+}
+
+// concurrencyTracker records the peak number of concurrent calls to track.
+type concurrencyTracker struct {
+	active atomic.Int32
+	peak   atomic.Int32
+}
+
+func (c *concurrencyTracker) track(n int) (int, error) {
+	active := c.active.Add(1)
+	defer c.active.Add(-1)
+	for {
+		peak := c.peak.Load()
+		if active <= peak || c.peak.CompareAndSwap(peak, active) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	return n, nil
+}
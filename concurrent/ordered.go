@@ -0,0 +1,143 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import "sync"
+
+// WithOrdered makes channel runs send their outputs in input order. At most window items are
+// processed or waiting to be sent at any time: once the window is full, no further input items are
+// read until the oldest item in the window is sent, so a single slow item holds back the whole
+// stream. A larger window tolerates more variance in processing time at the cost of memory.
+// Values lower than 1 are treated as 1. Runs over slices always preserve the input order and
+// ignore this option.
+func WithOrdered(window int) Option {
+	return func(config *config) {
+		config.window = max(window, 1)
+	}
+}
+
+// reorderBuffer restores the input order of the items coming out of a pipeline. The feeder
+// acquires a slot of the window before sending each item into the pipeline, and the slot is only
+// released once the item, or its failure, has left the buffer in order.
+type reorderBuffer[T any] struct {
+	slots   chan struct{}
+	dropped chan int
+	pending map[int]reordered[T]
+	next    int
+
+	mu sync.Mutex
+	// full is closed once the feeder waits for a slot, and replaced as soon as one is released.
+	full chan struct{}
+}
+
+// reordered is an item waiting in the reorder buffer. Failed items are kept as well, with ok set
+// to false, so that the items after them are not held back forever.
+type reordered[T any] struct {
+	value T
+	ok    bool
+}
+
+// newReorderBuffer returns a reorder buffer for the given window and hooks it up to the pipeline,
+// so that failed items are accounted for.
+func newReorderBuffer[T any](p *pipeline, window int) *reorderBuffer[T] {
+	b := &reorderBuffer[T]{
+		slots:   make(chan struct{}, window),
+		dropped: make(chan int),
+		pending: make(map[int]reordered[T], window),
+		full:    make(chan struct{}),
+	}
+	p.drop = func(index int) {
+		send(p.ctx, b.dropped, index)
+	}
+	p.stalled = b.stalled
+	return b
+}
+
+// acquire blocks until there is room in the window for another item. It reports false if the
+// pipeline stopped feeding items first.
+func (b *reorderBuffer[T]) acquire(p *pipeline) bool {
+	select {
+	case b.slots <- struct{}{}:
+		return true
+	default:
+	}
+	// Every item of the window is in the pipeline, so none of them may be held back waiting for
+	// more items, such as in a partial batch. The window is checked again under the lock, since a
+	// slot released meanwhile comes with a new full channel that must be closed as well.
+	b.mu.Lock()
+	select {
+	case b.slots <- struct{}{}:
+		b.mu.Unlock()
+		return true
+	default:
+	}
+	select {
+	case <-b.full:
+	default:
+		close(b.full)
+	}
+	b.mu.Unlock()
+	return send(p.feed, b.slots, struct{}{})
+}
+
+// release frees the slot of an item that left the buffer. The full channel is replaced before the
+// slot is freed, so that a feeder waiting for the slot closes the new one if it has to wait again.
+func (b *reorderBuffer[T]) release() {
+	b.mu.Lock()
+	select {
+	case <-b.full:
+		b.full = make(chan struct{})
+	default:
+	}
+	<-b.slots
+	b.mu.Unlock()
+}
+
+// stalled returns a channel that is closed while the feeder waits for room in the window.
+func (b *reorderBuffer[T]) stalled() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.full
+}
+
+// forward sends the items coming out of the pipeline to outputs in input order, until transformed
+// is closed. It reports false if the run was cancelled first.
+func (b *reorderBuffer[T]) forward(p *pipeline, transformed <-chan item[T], outputs chan<- T) bool {
+	for {
+		select {
+		case output, ok := <-transformed:
+			if !ok {
+				return true
+			}
+			b.pending[output.index] = reordered[T]{value: output.value, ok: true}
+		case index := <-b.dropped:
+			b.pending[index] = reordered[T]{}
+		case <-p.ctx.Done():
+			return false
+		}
+		for {
+			entry, ok := b.pending[b.next]
+			if !ok {
+				break
+			}
+			delete(b.pending, b.next)
+			b.next++
+			b.release()
+			if entry.ok && !deliver(p, outputs, entry.value) {
+				return false
+			}
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+// jitter is an action that returns its input after sleeping for a random duration, so that items
+// finish out of order.
+func jitter(n int) (int, error) {
+	time.Sleep(time.Duration(rand.IntN(500)) * time.Microsecond)
+	return n, nil
+}
+
+// countTo returns a closed channel holding the integers from 0 to n-1.
+func countTo(n int) <-chan int {
+	ch := make(chan int, n)
+	for i := 0; i < n; i++ {
+		ch <- i
+	}
+	close(ch)
+	return ch
+}
+
+func TestTransformChannelsOrdered(t *testing.T) {
+	before := runtime.NumGoroutine()
+	stage := concurrent.Then(
+		concurrent.NewStage(jitter, concurrent.WithWorkers(8)),
+		concurrent.NewStage(jitter, concurrent.WithWorkers(4)),
+	)
+
+	outputChan, errChan := stage.TransformChannels(context.Background(), countTo(500), concurrent.WithOrdered(16))
+
+	next := 0
+	for output := range outputChan {
+		if output != next {
+			t.Fatalf("Expected %d, got %d", next, output)
+		}
+		next++
+	}
+	if err := <-errChan; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if next != 500 {
+		t.Errorf("Expected 500 outputs, got %d", next)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestTransformChannelsOrderedWindow(t *testing.T) {
+	const window = 4
+	var started atomic.Int32
+	release := make(chan struct{})
+	stage := concurrent.NewStage(func(n int) (int, error) {
+		started.Add(1)
+		if n == 0 {
+			<-release
+		}
+		return n, nil
+	}, concurrent.WithWorkers(16))
+
+	outputChan, errChan := stage.TransformChannels(context.Background(), countTo(100), concurrent.WithOrdered(window))
+
+	// The first item holds back the window, so no more than window items may start.
+	time.Sleep(50 * time.Millisecond)
+	if n := started.Load(); n != window {
+		t.Errorf("Expected %d items to start while the window is full, got %d", window, n)
+	}
+	close(release)
+
+	next := 0
+	for output := range outputChan {
+		if output != next {
+			t.Fatalf("Expected %d, got %d", next, output)
+		}
+		next++
+	}
+	if err := <-errChan; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if next != 100 {
+		t.Errorf("Expected 100 outputs, got %d", next)
+	}
+}
+
+func TestTransformChannelsOrderedSkip(t *testing.T) {
+	before := runtime.NumGoroutine()
+	stage := concurrent.NewStage(failOnOdd, concurrent.WithWorkers(4))
+
+	outputChan, errChan := stage.TransformChannels(context.Background(), countTo(100),
+		concurrent.WithOrdered(2), concurrent.WithErrorPolicy(concurrent.Skip))
+
+	next := 0
+	for output := range outputChan {
+		if output != next {
+			t.Fatalf("Expected %d, got %d", next, output)
+		}
+		next += 2
+	}
+	if err := <-errChan; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if next != 100 {
+		t.Errorf("Expected the outputs to stop at 98, got %d", next-2)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestTransformChannelsOrderedCollectAll(t *testing.T) {
+	before := runtime.NumGoroutine()
+	stage := concurrent.NewStage(failOnOdd, concurrent.WithWorkers(4))
+
+	outputChan, errChan := stage.TransformChannels(context.Background(), countTo(20),
+		concurrent.WithOrdered(3), concurrent.WithErrorPolicy(concurrent.CollectAll))
+
+	failures := make(chan int)
+	go func() {
+		defer close(failures)
+		count := 0
+		for err := range errChan {
+			if errors.Is(err, errOdd) {
+				count++
+			}
+		}
+		failures <- count
+	}()
+	next := 0
+	for output := range outputChan {
+		if output != next {
+			t.Fatalf("Expected %d, got %d", next, output)
+		}
+		next += 2
+	}
+	if count := <-failures; count != 10 {
+		t.Errorf("Expected 10 failures, got %d", count)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestTransformChannelsOrderedCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stage := concurrent.NewStage(jitter, concurrent.WithWorkers(4))
+
+	// An input channel that is never closed; the cancellation must stop the reading.
+	inputChan := make(chan int)
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case inputChan <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	outputChan, errChan := stage.TransformChannels(ctx, inputChan, concurrent.WithOrdered(8))
+
+	for output := range outputChan {
+		if output == 50 {
+			cancel()
+		}
+	}
+	if err := <-errChan; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestTransformChannelsOrderedFailFast(t *testing.T) {
+	before := runtime.NumGoroutine()
+	stage := concurrent.NewStage(func(n int) (int, error) {
+		if n == 10 {
+			return 0, errOdd
+		}
+		return n, nil
+	}, concurrent.WithWorkers(4))
+
+	outputChan, errChan := stage.TransformChannels(context.Background(), countTo(1000), concurrent.WithOrdered(4))
+
+	next := 0
+	for output := range outputChan {
+		if next == 10 {
+			next++
+		}
+		if output != next {
+			t.Fatalf("Expected %d, got %d", next, output)
+		}
+		next++
+	}
+	if err := <-errChan; !errors.Is(err, errOdd) {
+		t.Errorf("Expected %v, got %v", errOdd, err)
+	}
+	if next < 10 {
+		t.Errorf("Expected the items before the failure to be sent, got %d", next)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestTransformChannelsOrderedFailFastOnlyErrors(t *testing.T) {
+	before := runtime.NumGoroutine()
+	stage := concurrent.NewStage(failOnOdd, concurrent.WithWorkers(4))
+
+	_, errChan := stage.TransformChannels(context.Background(), countTo(100), concurrent.WithOrdered(4))
+
+	// Only the error channel is read; the output channel is abandoned.
+	if err := <-errChan; !errors.Is(err, errOdd) {
+		t.Errorf("Expected %v, got %v", errOdd, err)
+	}
+	assertNoGoroutineLeak(t, before)
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error reported for an item whose action panicked. Workers recover panics by
+// default and report them through the error path, halting the run under Skip as under FailFast;
+// use the WithRethrowPanics option to let them crash the program instead.
+type PanicError struct {
+	// Value is the value the action panicked with.
+	Value any
+	// Stack is the stack trace of the worker goroutine at the time of the panic.
+	Stack []byte
+	// Index is the position of the item in the input.
+	Index int
+	// Stage is the position of the stage that panicked, counting the stages created with
+	// NewStage from zero.
+	Stage int
+}
+
+// Error returns a string representation of the error.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in stage %d on item %d: %v", e.Stage, e.Index, e.Value)
+}
+
+// Unwrap returns the value the action panicked with if it is an error, or nil otherwise.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// WithRethrowPanics makes workers let panics in actions propagate, crashing the program, instead
+// of recovering them as a *PanicError.
+func WithRethrowPanics() Option {
+	return func(config *config) {
+		config.rethrowPanics = true
+	}
+}
+
+// protect calls the action on the input item, recovering a panic as a *PanicError unless the run
+// rethrows panics.
+func protect[In any, Out any](p *pipeline, step int, action TransformActionContext[In, Out], input item[In]) (output Out, err error) {
+	if !p.config.rethrowPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &PanicError{Value: r, Stack: debug.Stack(), Index: input.index, Stage: step}
+			}
+		}()
+	}
+	return action(p.ctx, input.value)
+}
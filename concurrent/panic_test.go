@@ -0,0 +1,152 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+func TestPanicRecoveredWithError(t *testing.T) {
+	before := runtime.NumGoroutine()
+	transformer := concurrent.NewTransformer[int, int](3)
+
+	_, err := transformer.TransformWithError([]int{1, 2, 3}, func(n int) (int, error) {
+		return n, nil
+	}, panicOn(2))
+
+	var panicErr *concurrent.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected *PanicError, got: %v", err)
+	}
+	if panicErr.Value != "boom" || panicErr.Index != 1 || panicErr.Stage != 1 {
+		t.Errorf("Unexpected panic error: %v", panicErr)
+	}
+	if !strings.Contains(string(panicErr.Stack), "panic_test.go") {
+		t.Errorf("Expected stack trace to point at the panicking action, got:\n%s", panicErr.Stack)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestPanicRecoveredWithErrorValue(t *testing.T) {
+	failure := errors.New("failure")
+	stage := concurrent.NewStage(func(int) (int, error) {
+		panic(failure)
+	})
+
+	_, err := stage.Transform(context.Background(), []int{1})
+
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected the panic value to be unwrapped, got: %v", err)
+	}
+}
+
+func TestPanicRecoveredChannels(t *testing.T) {
+	transformer := concurrent.NewTransformer[int, int](2)
+	inputChan := make(chan int, 3)
+	inputChan <- 1
+	inputChan <- 2
+	inputChan <- 3
+	close(inputChan)
+
+	outputChan, errChan := transformer.TransformChannelsWithError(inputChan, panicOn(3))
+
+	for range outputChan {
+	}
+	var panicErr *concurrent.PanicError
+	if err := <-errChan; !errors.As(err, &panicErr) || panicErr.Index != 2 {
+		t.Errorf("Expected *PanicError for item 2, got: %v", err)
+	}
+}
+
+func TestPanicRethrownByTransform(t *testing.T) {
+	defer func() {
+		r := recover()
+		if _, ok := r.(*concurrent.PanicError); !ok {
+			t.Errorf("Expected a *PanicError panic, got: %v", r)
+		}
+	}()
+
+	transformer := concurrent.NewTransformer[int, int](2)
+	transformer.Transform([]int{1, 2}, func(n int) int {
+		if n == 2 {
+			panic("boom")
+		}
+		return n
+	})
+}
+
+func TestPanicReportedUnderSkip(t *testing.T) {
+	transformer := concurrent.NewTransformer[int, int](2, concurrent.WithErrorPolicy(concurrent.Skip))
+
+	outputs, err := transformer.TransformWithError([]int{1, 2, 3}, panicOn(2))
+
+	var panicErr *concurrent.PanicError
+	if !errors.As(err, &panicErr) || panicErr.Index != 1 {
+		t.Errorf("Expected *PanicError for item 1, got: %v", err)
+	}
+	if outputs != nil {
+		t.Errorf("Expected no outputs, got %v", outputs)
+	}
+
+	defer func() {
+		if _, ok := recover().(*concurrent.PanicError); !ok {
+			t.Error("Expected Transform to panic with a *PanicError")
+		}
+	}()
+	transformer.Transform([]int{1, 2, 3}, func(n int) int {
+		if n == 2 {
+			panic("boom")
+		}
+		return n
+	})
+}
+
+func TestWithRethrowPanics(t *testing.T) {
+	if os.Getenv("CONCURRENT_RETHROW_PANICS") == "1" {
+		stage := concurrent.NewStage(panicOn(1))
+		stage.Transform(context.Background(), []int{1}, concurrent.WithRethrowPanics())
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestWithRethrowPanics$")
+	cmd.Env = append(os.Environ(), "CONCURRENT_RETHROW_PANICS=1")
+	output, err := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("Expected the process to crash, got: %v\n%s", err, output)
+	}
+	if !strings.Contains(string(output), "panic: boom") {
+		t.Errorf("Expected the original panic in the output, got:\n%s", output)
+	}
+}
+
+// panicOn returns an action that panics with "boom" for the given value.
+func panicOn(value int) concurrent.TransformActionWithError[int, int] {
+	return func(n int) (int, error) {
+		if n == value {
+			panic("boom")
+		}
+		return n, nil
+	}
+}
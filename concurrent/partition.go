@@ -0,0 +1,98 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import (
+	"context"
+	"hash/maphash"
+	"sync"
+)
+
+// NewPartitionedStage returns a Stage that applies the action on its input items with the items
+// partitioned by key: every key is assigned to one of the workers by hashing it, so items with the
+// same key are processed one at a time, in the order they reach the stage, and are sent on in
+// that order, while items with different keys are processed concurrently. This keeps the order of
+// the items of each key in the output channel of TransformChannels, without the cost of keeping
+// the global order.
+//
+// Per-key order is relative to the order items reach the stage, so the stages before it, if any,
+// must not reorder items of the same key, for example by having a single worker; likewise for
+// the stages after it. A slow item holds back the other keys of its worker. A panic in the key
+// function is handled like a panic in the action.
+func NewPartitionedStage[In any, Out any, K comparable](key func(In) K, action TransformActionWithError[In, Out], opts ...StageOption) Stage[In, Out] {
+	config := newStageConfig(opts)
+	stageAction := decorate(func(_ context.Context, value In) (Out, error) {
+		return action(value)
+	}, config)
+	// maphash.Comparable, which the module requires Go 1.24 for, hashes equal keys alike for any
+	// comparable type, unlike hashing a formatted key, which tells 0.0 from -0.0.
+	seed := maphash.MakeSeed()
+	hash := func(_ context.Context, value In) (uint64, error) {
+		return maphash.Comparable(seed, key(value)), nil
+	}
+	return Stage[In, Out]{
+		steps: 1,
+		run: func(p *pipeline, step int, in <-chan item[In]) <-chan item[Out] {
+			out := make(chan item[Out], config.buffer)
+			partitions := make([]chan item[In], config.workers)
+			for i := range partitions {
+				partitions[i] = make(chan item[In])
+			}
+
+			// Route every item to the partition of its key.
+			p.goroutine(func() {
+				defer func() {
+					for _, partition := range partitions {
+						close(partition)
+					}
+				}()
+				for input := range receive(p.ctx, in) {
+					h, err := protect(p, step, hash, input)
+					if err != nil {
+						p.fail(failed(p, step, input, err))
+						continue
+					}
+					if !send(p.ctx, partitions[h%uint64(len(partitions))], input) {
+						return
+					}
+				}
+			})
+
+			// Process every partition sequentially.
+			var wg sync.WaitGroup
+			wg.Add(len(partitions))
+			for worker, partition := range partitions {
+				process(p, 1, partition,
+					func(_, queue int, input item[In]) (Out, error) {
+						return observe(p, step, worker, queue, stageAction, input)
+					},
+					func(input item[In], output Out, err error) bool {
+						if err != nil {
+							p.fail(failed(p, step, input, err))
+							return true
+						}
+						return send(p.ctx, out, item[Out]{index: input.index, value: output})
+					},
+					wg.Done,
+				)
+			}
+			p.goroutine(func() {
+				wg.Wait()
+				close(out)
+			})
+			return out
+		},
+	}
+}
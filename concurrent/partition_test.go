@@ -0,0 +1,121 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+// event is an item of a stream of events of many users.
+type event struct {
+	user int
+	seq  int
+}
+
+func TestPartitionedStage(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []concurrent.Option
+	}{
+		{name: "goroutines"},
+		{name: "pool", opts: []concurrent.Option{concurrent.WithPool(startPool(t, 4, 4))}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			before := runtime.NumGoroutine()
+			const users, events = 8, 10
+			var tracker concurrencyTracker
+			var mu sync.Mutex
+			active := make(map[int]bool)
+			stage := concurrent.NewPartitionedStage(func(e event) int { return e.user }, func(e event) (event, error) {
+				mu.Lock()
+				if active[e.user] {
+					t.Errorf("Expected the events of user %d to be processed one at a time", e.user)
+				}
+				active[e.user] = true
+				mu.Unlock()
+				tracker.track(0)
+				mu.Lock()
+				active[e.user] = false
+				mu.Unlock()
+				return e, nil
+			}, concurrent.WithWorkers(4))
+
+			inputChan := make(chan event, users*events)
+			for seq := 0; seq < events; seq++ {
+				for user := 0; user < users; user++ {
+					inputChan <- event{user: user, seq: seq}
+				}
+			}
+			close(inputChan)
+			outputChan, errChan := stage.TransformChannels(context.Background(), inputChan, tc.opts...)
+
+			next := make(map[int]int)
+			count := 0
+			for e := range outputChan {
+				if e.seq != next[e.user] {
+					t.Fatalf("Expected event %d of user %d, got %d", next[e.user], e.user, e.seq)
+				}
+				next[e.user]++
+				count++
+			}
+			if err := <-errChan; err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if count != users*events {
+				t.Errorf("Expected %d outputs, got %d", users*events, count)
+			}
+			if peak := tracker.peak.Load(); peak < 2 || peak > 4 {
+				t.Errorf("Expected different users to be processed by up to 4 workers, peak was %d", peak)
+			}
+			assertNoGoroutineLeak(t, before)
+		})
+	}
+}
+
+func TestPartitionedStageError(t *testing.T) {
+	stage := concurrent.NewPartitionedStage(func(n int) int { return n % 3 }, failOnOdd, concurrent.WithWorkers(2))
+
+	result, err := stage.Transform(context.Background(), []int{0, 1, 2, 3, 4},
+		concurrent.WithErrorPolicy(concurrent.Skip))
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result) != 3 || result[0] != 0 || result[1] != 2 || result[2] != 4 {
+		t.Errorf("Expected [0 2 4], got %v", result)
+	}
+}
+
+func TestPartitionedStageKeyPanic(t *testing.T) {
+	stage := concurrent.NewPartitionedStage(func(n int) int {
+		if n == 2 {
+			panic("bad key")
+		}
+		return n
+	}, func(n int) (int, error) { return n, nil })
+
+	_, err := stage.Transform(context.Background(), []int{1, 2, 3})
+
+	var panicErr *concurrent.PanicError
+	if !errors.As(err, &panicErr) || panicErr.Index != 1 {
+		t.Errorf("Expected a *PanicError for item 1, got %v", err)
+	}
+}
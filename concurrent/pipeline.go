@@ -0,0 +1,166 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// item is an element flowing through a pipeline, tagged with the position of the input item it
+// originates from.
+type item[T any] struct {
+	index int
+	value T
+}
+
+// pipeline holds the state shared by all stages during a single run.
+type pipeline struct {
+	config config
+
+	// ctx is done when the run is cancelled, which stops every goroutine of the run.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// feed is done when no more input items should be fed into the pipeline.
+	feed        context.Context
+	stopFeeding context.CancelFunc
+
+	// graceful makes a FailFast failure only stop the feeding of new items, letting the items
+	// already in the pipeline run to completion, instead of cancelling the run.
+	graceful bool
+
+	// report, if set, is called with every failure that the error policy reports, as soon as it
+	// happens.
+	report func(error)
+
+	// received, if set, reports whether the caller received the failure that stopped the feeding
+	// of a graceful run.
+	received func() bool
+
+	// drop, if set, is called with the index of every failed item, whatever the error policy.
+	drop func(index int)
+
+	// input, if set, returns the input item at the given index, for runs over slices.
+	input func(index int) any
+
+	// stalled, if set, returns a channel that is closed while the run takes in no more items until
+	// some of those already in it are sent, so that stages holding items back let go of them.
+	stalled func() <-chan struct{}
+
+	wg sync.WaitGroup
+
+	mu sync.Mutex
+	// failure is the failure that halted the run under FailFast.
+	failure error
+	// failures holds every failure under CollectAll.
+	failures []*ItemError
+}
+
+func newPipeline(ctx context.Context, config config) *pipeline {
+	p := &pipeline{config: config}
+	p.ctx, p.cancel = context.WithCancel(ctx)
+	p.feed, p.stopFeeding = context.WithCancel(p.ctx)
+	return p
+}
+
+// failed returns the error of an item that failed at the given step. Past the first step of a
+// channel run, the input item is no longer around, so the error does not hold it.
+func failed[T any](p *pipeline, step int, input item[T], err error) *ItemError {
+	itemErr := &ItemError{Index: input.index, Stage: step, Err: err}
+	switch {
+	case p.input != nil:
+		itemErr.Input = p.input(input.index)
+	case step == 0:
+		itemErr.Input = input.value
+	}
+	return itemErr
+}
+
+// goroutine runs fn in a new goroutine tracked by the run's wait group.
+func (p *pipeline) goroutine(fn func()) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		fn()
+	}()
+}
+
+// fail handles a failed item according to the error policy. A panic is a programming error rather
+// than a failed item, so it halts the run even under Skip.
+func (p *pipeline) fail(err *ItemError) {
+	policy := p.config.errorPolicy
+	var panicErr *PanicError
+	if policy == Skip && errors.As(err.Err, &panicErr) {
+		policy = FailFast
+	}
+	switch policy {
+	case FailFast:
+		p.mu.Lock()
+		first := p.failure == nil
+		if first {
+			p.failure = err
+		}
+		p.mu.Unlock()
+		if first {
+			// Report the failure before stopping the run, so that it is not lost to a
+			// cancellation.
+			if p.report != nil {
+				p.report(err)
+			}
+			p.stopFeeding()
+			if !p.graceful {
+				p.cancel()
+			}
+		}
+	case CollectAll:
+		p.mu.Lock()
+		p.failures = append(p.failures, err)
+		p.mu.Unlock()
+		if p.report != nil {
+			p.report(err)
+		}
+	}
+	// The item is only dropped once the run stopped feeding on a FailFast failure, since the
+	// outputs may no longer be read from then on.
+	if p.drop != nil {
+		p.drop(err.Index)
+	}
+}
+
+// err returns the reason the run was halted or, under CollectAll, the joined failures, or nil if
+// there are none. It must only be called after every goroutine of the run has exited.
+func (p *pipeline) err() error {
+	if p.failure != nil {
+		return p.failure
+	}
+	if p.ctx.Err() != nil {
+		return context.Cause(p.ctx)
+	}
+	if len(p.failures) > 0 {
+		slices.SortFunc(p.failures, func(a, b *ItemError) int {
+			return cmp.Or(cmp.Compare(a.Index, b.Index), cmp.Compare(a.Stage, b.Stage))
+		})
+		errs := make([]error, len(p.failures))
+		for i, failure := range p.failures {
+			errs[i] = failure
+		}
+		return errors.Join(errs...)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrPoolSaturated is returned when a task is submitted to a pool whose queue is full.
+	ErrPoolSaturated = errors.New("pool queue is full")
+
+	// ErrPoolClosed is returned when a task is submitted to a pool that is not running, either
+	// because it was not started yet or because it was shut down.
+	ErrPoolClosed = errors.New("pool is not running")
+)
+
+// Pool is a long-lived set of workers that run submitted tasks. Runs configured with the WithPool
+// option execute their actions on the pool instead of starting goroutines for every worker of
+// every stage, which saves the setup cost for frequent, small runs.
+//
+// A Pool must be started with Start before tasks are accepted, and stopped with Shutdown, which
+// waits for the queued tasks to finish. Its methods are safe for concurrent use.
+type Pool struct {
+	workers int
+	tasks   chan func()
+
+	// closing is closed when Shutdown is called, which unblocks the pending calls to SubmitWait.
+	closing   chan struct{}
+	closeOnce sync.Once
+	// done is closed once every worker has exited.
+	done chan struct{}
+
+	mu      sync.RWMutex
+	state   poolState
+	running sync.WaitGroup
+}
+
+type poolState int
+
+const (
+	poolIdle poolState = iota
+	poolRunning
+	poolClosed
+)
+
+// NewPool returns a new Pool with the given number of workers, which holds up to queue tasks
+// waiting for a free worker. A queue of 0 only accepts tasks when a worker is idle. At least one
+// worker is always started, and negative queue lengths are treated as 0.
+func NewPool(workers, queue int) *Pool {
+	return &Pool{
+		workers: max(workers, 1),
+		tasks:   make(chan func(), max(queue, 0)),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start starts the workers of the pool. Calling Start on a pool that is already running or that
+// was shut down has no effect.
+func (pool *Pool) Start() {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if pool.state != poolIdle {
+		return
+	}
+	pool.state = poolRunning
+	for i := 0; i < pool.workers; i++ {
+		pool.running.Add(1)
+		go func() {
+			defer pool.running.Done()
+			for task := range pool.tasks {
+				task()
+			}
+		}()
+	}
+	go func() {
+		pool.running.Wait()
+		close(pool.done)
+	}()
+}
+
+// Submit queues the task to be run by one of the workers without blocking. It returns
+// ErrPoolSaturated if the queue is full, or ErrPoolClosed if the pool is not running. A task that
+// panics crashes the program.
+func (pool *Pool) Submit(task func()) error {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	if pool.state != poolRunning {
+		return ErrPoolClosed
+	}
+	select {
+	case pool.tasks <- task:
+		return nil
+	default:
+		return ErrPoolSaturated
+	}
+}
+
+// SubmitWait is like Submit, but waits for room in the queue instead of returning
+// ErrPoolSaturated. It returns the context's error if ctx is done first, or ErrPoolClosed if the
+// pool is shut down while waiting.
+func (pool *Pool) SubmitWait(ctx context.Context, task func()) error {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	if pool.state != poolRunning {
+		return ErrPoolClosed
+	}
+	select {
+	case pool.tasks <- task:
+		return nil
+	case <-pool.closing:
+		return ErrPoolClosed
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// Shutdown stops the pool from accepting tasks and waits for the queued and running tasks to
+// finish. If ctx is done first, Shutdown returns the context's error while the workers keep
+// draining the queue in the background; Shutdown may be called again to wait for them.
+func (pool *Pool) Shutdown(ctx context.Context) error {
+	pool.closeOnce.Do(func() {
+		close(pool.closing)
+		pool.mu.Lock()
+		started := pool.state == poolRunning
+		pool.state = poolClosed
+		close(pool.tasks)
+		pool.mu.Unlock()
+		if !started {
+			close(pool.done)
+		}
+	})
+	select {
+	case <-pool.done:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// WithPool makes the run execute the actions of every stage as tasks of the pool, which must be
+// running. Each stage still keeps no more than its number of workers of items in flight, but
+// only starts a single goroutine to dispatch them. When the queue of the pool is full, the stage
+// waits for room rather than failing the item, so runs sharing a busy pool slow down instead of
+// failing. Items submitted after the pool was shut down fail with ErrPoolClosed, wrapped in an
+// *ItemError, and are handled according to the error policy.
+func WithPool(pool *Pool) Option {
+	return func(config *config) {
+		config.pool = pool
+	}
+}
+
+// pooled is the outcome of a unit of work run as a task of a pool.
+type pooled[U any, R any] struct {
+	unit   U
+	worker int
+	result R
+	err    error
+}
+
+// dispatch computes the units of work received from in as tasks of the pool, keeping at most
+// workers of them in flight, and hands the results to finish. The tasks never block on the
+// pipeline, so that a slow consumer cannot hold up the workers of a shared pool: their outcomes are
+// handed back through a channel with room for every task in flight, and finish is called from the
+// dispatching goroutine. Units that the pool rejects are finished with the rejection error. It
+// returns once every task it submitted has finished.
+func dispatch[U any, R any](p *pipeline, workers int, in <-chan U, compute func(worker, queue int, unit U) (R, error), finish func(unit U, result R, err error) bool) {
+	outcomes := make(chan pooled[U, R], workers)
+	inFlight := 0
+	// free holds the identifiers of the workers of the stage that have no unit in flight.
+	free := make([]int, workers)
+	for i := range free {
+		free[i] = workers - 1 - i
+	}
+	defer func() {
+		for ; inFlight > 0; inFlight-- {
+			<-outcomes
+		}
+	}()
+	for in != nil || inFlight > 0 {
+		var next <-chan U
+		if inFlight < workers {
+			next = in
+		}
+		select {
+		case unit, ok := <-next:
+			if !ok {
+				in = nil
+				continue
+			}
+			worker, queue := free[len(free)-1], len(in)
+			err := p.config.pool.SubmitWait(p.ctx, func() {
+				result, err := compute(worker, queue, unit)
+				outcomes <- pooled[U, R]{unit: unit, worker: worker, result: result, err: err}
+			})
+			if err == nil {
+				// Count the task before anything else, so that it is waited for even if the
+				// run was cancelled meanwhile.
+				free = free[:len(free)-1]
+				inFlight++
+			}
+			if p.ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				var zero R
+				if !finish(unit, zero, err) {
+					return
+				}
+			}
+		case outcome := <-outcomes:
+			free = append(free, outcome.worker)
+			inFlight--
+			if !finish(outcome.unit, outcome.result, outcome.err) {
+				return
+			}
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+func TestPoolShutdownDrainsQueue(t *testing.T) {
+	before := runtime.NumGoroutine()
+	pool := concurrent.NewPool(2, 10)
+	pool.Start()
+
+	var ran atomic.Int32
+	for i := 0; i < 10; i++ {
+		if err := pool.Submit(func() {
+			time.Sleep(time.Millisecond)
+			ran.Add(1)
+		}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if n := ran.Load(); n != 10 {
+		t.Errorf("Expected 10 tasks to run, got %d", n)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestPoolSaturated(t *testing.T) {
+	pool := concurrent.NewPool(1, 1)
+	pool.Start()
+	defer pool.Shutdown(context.Background())
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	if err := pool.Submit(func() {
+		close(started)
+		<-release
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	<-started
+	if err := pool.Submit(func() {}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := pool.Submit(func() {}); !errors.Is(err, concurrent.ErrPoolSaturated) {
+		t.Errorf("Expected %v, got %v", concurrent.ErrPoolSaturated, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := pool.SubmitWait(ctx, func() {}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	close(release)
+	if err := pool.SubmitWait(context.Background(), func() {}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestPoolClosed(t *testing.T) {
+	pool := concurrent.NewPool(1, 1)
+
+	if err := pool.Submit(func() {}); !errors.Is(err, concurrent.ErrPoolClosed) {
+		t.Errorf("Expected %v before Start, got %v", concurrent.ErrPoolClosed, err)
+	}
+	pool.Start()
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := pool.Submit(func() {}); !errors.Is(err, concurrent.ErrPoolClosed) {
+		t.Errorf("Expected %v after Shutdown, got %v", concurrent.ErrPoolClosed, err)
+	}
+	if err := pool.SubmitWait(context.Background(), func() {}); !errors.Is(err, concurrent.ErrPoolClosed) {
+		t.Errorf("Expected %v after Shutdown, got %v", concurrent.ErrPoolClosed, err)
+	}
+}
+
+func TestPoolShutdownUnblocksSubmitWait(t *testing.T) {
+	pool := concurrent.NewPool(1, 0)
+	pool.Start()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	if err := pool.SubmitWait(context.Background(), func() {
+		close(started)
+		<-release
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	<-started
+
+	errs := make(chan error)
+	go func() {
+		errs <- pool.SubmitWait(context.Background(), func() {})
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := pool.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded while a task is running, got %v", err)
+	}
+	if err := <-errs; !errors.Is(err, concurrent.ErrPoolClosed) {
+		t.Errorf("Expected %v, got %v", concurrent.ErrPoolClosed, err)
+	}
+
+	close(release)
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestTransformerWithPool(t *testing.T) {
+	pool := concurrent.NewPool(4, 16)
+	pool.Start()
+	defer pool.Shutdown(context.Background())
+	transformer := concurrent.NewTransformer[string, string](4, concurrent.WithPool(pool))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := transformer.TransformWithError([]string{"a", "b", "c", "d", "e"}, upperCase, lowerCase)
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if got := strings.Join(result, ","); got != "a,b,c,d,e" {
+				t.Errorf("Expected a,b,c,d,e, got %s", got)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStageWithPoolChannels(t *testing.T) {
+	pool := concurrent.NewPool(4, 8)
+	pool.Start()
+	defer pool.Shutdown(context.Background())
+	stage := concurrent.Then(
+		concurrent.NewStage(jitter, concurrent.WithWorkers(4)),
+		concurrent.NewStage(failOnOdd, concurrent.WithWorkers(4)),
+	)
+
+	outputChan, errChan := stage.TransformChannels(context.Background(), countTo(100),
+		concurrent.WithPool(pool), concurrent.WithOrdered(8), concurrent.WithErrorPolicy(concurrent.Skip))
+
+	next := 0
+	for output := range outputChan {
+		if output != next {
+			t.Fatalf("Expected %d, got %d", next, output)
+		}
+		next += 2
+	}
+	if err := <-errChan; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if next != 100 {
+		t.Errorf("Expected the outputs to stop at 98, got %d", next-2)
+	}
+}
+
+func TestStageWithPoolRejected(t *testing.T) {
+	before := runtime.NumGoroutine()
+	pool := concurrent.NewPool(1, 1)
+	pool.Start()
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err := concurrent.NewStage(length).Transform(context.Background(), []string{"a", "b"}, concurrent.WithPool(pool))
+
+	var itemErr *concurrent.ItemError
+	if !errors.As(err, &itemErr) || !errors.Is(err, concurrent.ErrPoolClosed) {
+		t.Errorf("Expected an *ItemError wrapping %v, got %v", concurrent.ErrPoolClosed, err)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestStageWithPoolContextCancel(t *testing.T) {
+	pool := concurrent.NewPool(2, 4)
+	pool.Start()
+	defer pool.Shutdown(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var ran atomic.Int32
+	stage := concurrent.NewStage(func(n int) (int, error) {
+		if ran.Add(1) == 3 {
+			cancel()
+		}
+		time.Sleep(time.Millisecond)
+		return n, nil
+	}, concurrent.WithWorkers(2))
+
+	items := make([]int, 1000)
+	_, err := stage.Transform(ctx, items, concurrent.WithPool(pool))
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if n := ran.Load(); n >= 1000 {
+		t.Errorf("Expected the cancellation to stop the processing, but %d items ran", n)
+	}
+}
+
+func TestTransformerWithPoolNotStarted(t *testing.T) {
+	pool := concurrent.NewPool(2, 0)
+	transformer := concurrent.NewTransformer[string, string](2, concurrent.WithPool(pool))
+
+	_, err := transformer.TransformWithError([]string{"a", "b"}, upperCase)
+	if !errors.Is(err, concurrent.ErrPoolClosed) {
+		t.Errorf("Expected %v, got %v", concurrent.ErrPoolClosed, err)
+	}
+
+	inputChan := make(chan string, 2)
+	inputChan <- "a"
+	inputChan <- "b"
+	close(inputChan)
+	for output := range transformer.TransformChannels(inputChan, strings.ToUpper) {
+		t.Errorf("Unexpected output %s", output)
+	}
+
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, concurrent.ErrPoolClosed) {
+			t.Errorf("Expected a panic with %v, got %v", concurrent.ErrPoolClosed, err)
+		}
+	}()
+	transformer.Transform([]string{"a", "b"}, strings.ToUpper)
+	t.Error("Expected Transform to panic")
+}
+
+func TestTransformerTransformWithPool(t *testing.T) {
+	pool := startPool(t, 1, 0)
+	transformer := concurrent.NewTransformer[int, int](4, concurrent.WithPool(pool))
+	var running, peak atomic.Int32
+	action := func(n int) int {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+		time.Sleep(100 * time.Microsecond)
+		return n
+	}
+
+	result := transformer.Transform([]int{1, 2, 3, 4, 5, 6, 7, 8}, action)
+
+	if len(result) != 8 || result[7] != 8 {
+		t.Errorf("Unexpected result: %v", result)
+	}
+	// The pool has a single worker, so the actions never run concurrently.
+	if n := peak.Load(); n != 1 {
+		t.Errorf("Expected the actions to run on the pool, got %d running at once", n)
+	}
+}
+
+func TestStageWithPoolWaitsForTasks(t *testing.T) {
+	pool := startPool(t, 4, 4)
+	for i := 0; i < 50; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		var running atomic.Int32
+		stage := concurrent.NewStage(func(n int) (int, error) {
+			running.Add(1)
+			defer running.Add(-1)
+			if n == 2 {
+				cancel()
+			}
+			time.Sleep(100 * time.Microsecond)
+			return n, nil
+		}, concurrent.WithWorkers(4))
+
+		_, err := stage.Transform(ctx, make([]int, 20), concurrent.WithPool(pool))
+
+		if n := running.Load(); n != 0 {
+			t.Fatalf("Expected every action to have returned, %d still running (error: %v)", n, err)
+		}
+		cancel()
+	}
+}
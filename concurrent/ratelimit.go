@@ -0,0 +1,85 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket that limits how often an action is called. The bucket holds up to
+// burst tokens and is refilled at rate tokens per second; every call takes a token, waiting for one
+// to be available if the bucket is empty. A RateLimiter is safe for concurrent use, and may be
+// shared by several stages to put them under a common budget.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a RateLimiter that allows rate calls per second on average, and bursts of
+// up to burst calls. The bucket starts full. Values of burst lower than 1 are treated as 1. It
+// panics if rate is not positive.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if rate <= 0 {
+		panic("rate limiter requires a positive rate")
+	}
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(max(burst, 1)),
+		tokens: float64(max(burst, 1)),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available and takes it. It returns the context's error, without
+// taking a token, if ctx is done first.
+func (limiter *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	limiter.mu.Lock()
+	now := time.Now()
+	limiter.tokens = min(limiter.burst, limiter.tokens+now.Sub(limiter.last).Seconds()*limiter.rate)
+	limiter.last = now
+	// Take the token right away, even if the bucket goes into debt, so that concurrent callers
+	// are served in the order they arrived.
+	limiter.tokens--
+	wait := time.Duration(-limiter.tokens / limiter.rate * float64(time.Second))
+	limiter.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	if err := sleep(ctx, wait); err != nil {
+		limiter.mu.Lock()
+		limiter.tokens++
+		limiter.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// WithRateLimit makes every call to the stage's action, including each retry, wait for a token of
+// the limiter. Waiting respects the cancellation of the run and the timeout of the attempt.
+func WithRateLimit(limiter *RateLimiter) StageOption {
+	return func(config *stageConfig) {
+		config.limiter = limiter
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter := concurrent.NewRateLimiter(1, 3)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected the burst to go through without waiting, took %v", elapsed)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded once the bucket is empty, got %v", err)
+	}
+}
+
+func TestRateLimiterPanicsOnInvalidRate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic")
+		}
+	}()
+	concurrent.NewRateLimiter(0, 1)
+}
+
+func TestStageWithRateLimit(t *testing.T) {
+	// 20 items at 200 per second, with a burst of 4, take at least 80ms.
+	limiter := concurrent.NewRateLimiter(200, 4)
+	stage := concurrent.NewStage(func(n int) (int, error) { return n, nil },
+		concurrent.WithWorkers(8), concurrent.WithRateLimit(limiter))
+
+	start := time.Now()
+	result, err := stage.Transform(context.Background(), make([]int, 20))
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result) != 20 {
+		t.Errorf("Expected 20 outputs, got %d", len(result))
+	}
+	if elapsed < 80*time.Millisecond {
+		t.Errorf("Expected the rate limit to take at least 80ms, took %v", elapsed)
+	}
+}
+
+func TestStageWithRateLimitContextCancel(t *testing.T) {
+	limiter := concurrent.NewRateLimiter(1, 1)
+	stage := concurrent.NewStage(func(n int) (int, error) { return n, nil },
+		concurrent.WithWorkers(2), concurrent.WithRateLimit(limiter))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := stage.Transform(ctx, make([]int, 10))
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the cancellation to interrupt the wait, took %v", elapsed)
+	}
+}
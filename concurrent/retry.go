@@ -0,0 +1,155 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy describes how a stage retries an action that returns an error. Attach it to a stage
+// with the WithRetry option.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times the action is called for an item, including the
+	// first call. Values lower than 1 are treated as 1, which disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the time waited before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the time waited before any retry. Zero means no cap.
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor the backoff grows by after each retry. Values lower than 1 are
+	// treated as 2, giving an exponential backoff.
+	Multiplier float64
+
+	// Jitter is the fraction, between 0 and 1, of each backoff that is randomized. A jitter of 0.2
+	// waits anywhere between 80% and 100% of the computed backoff, which spreads out retries of
+	// items that failed together.
+	Jitter float64
+
+	// Retryable reports whether an error is worth retrying. If nil, every error is retried.
+	Retryable func(error) bool
+
+	// AttemptTimeout bounds each attempt by cancelling the context passed to the action after
+	// the given duration. It only interrupts actions that take a context, such as those of stages
+	// created with NewStageContext. Zero means no timeout.
+	AttemptTimeout time.Duration
+}
+
+// WithRetry makes the stage retry its action according to the policy. Errors returned by a stage
+// with a retry policy are wrapped in a *RetryError, which records the number of attempts made.
+func WithRetry(policy RetryPolicy) StageOption {
+	return func(config *stageConfig) {
+		config.retry = &policy
+	}
+}
+
+// RetryError is returned by a stage with a retry policy when its action keeps failing, either
+// because the attempts ran out or because the last error was not retryable.
+type RetryError struct {
+	// Attempts is the number of times the action was called.
+	Attempts int
+	// Err is the error returned by the last attempt, or the context's error if the run was
+	// cancelled while waiting to retry.
+	Err error
+}
+
+// Error returns a string representation of the error.
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("gave up after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// retry calls the action on value until it succeeds or the policy gives up, waiting between
+// attempts while ctx is not done.
+func retry[In any, Out any](ctx context.Context, policy *RetryPolicy, action TransformActionContext[In, Out], value In) (Out, error) {
+	maxAttempts := max(policy.MaxAttempts, 1)
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	backoff := policy.InitialBackoff
+	if policy.MaxBackoff > 0 {
+		backoff = min(backoff, policy.MaxBackoff)
+	}
+	for attempt := 1; ; attempt++ {
+		output, err := attemptAction(ctx, policy.AttemptTimeout, action, value)
+		if err == nil {
+			return output, nil
+		}
+		if attempt >= maxAttempts || (policy.Retryable != nil && !policy.Retryable(err)) {
+			return output, &RetryError{Attempts: attempt, Err: err}
+		}
+		delay := backoff
+		if jitter := min(max(policy.Jitter, 0), 1); jitter > 0 {
+			delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return output, &RetryError{Attempts: attempt, Err: err}
+		}
+		backoff = nextBackoff(backoff, multiplier, policy.MaxBackoff)
+	}
+}
+
+// nextBackoff returns the backoff that follows the given one, capped at maxBackoff if it is
+// positive. The growth saturates instead of overflowing time.Duration, however many retries
+// are made.
+func nextBackoff(backoff time.Duration, multiplier float64, maxBackoff time.Duration) time.Duration {
+	limit := time.Duration(math.MaxInt64)
+	if maxBackoff > 0 {
+		limit = maxBackoff
+	}
+	next := float64(backoff) * multiplier
+	if next >= float64(limit) {
+		return limit
+	}
+	return time.Duration(next)
+}
+
+// attemptAction calls the action once, bounding the context passed to it by timeout if it is
+// positive.
+func attemptAction[In any, Out any](ctx context.Context, timeout time.Duration, action TransformActionContext[In, Out], value In) (Out, error) {
+	if timeout <= 0 {
+		return action(ctx, value)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return action(ctx, value)
+}
+
+// sleep waits for the given duration, returning early with the context's error if ctx is done
+// first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+func TestRetrySucceeds(t *testing.T) {
+	flaky := newFlakyAction(2)
+	stage := concurrent.NewStage(flaky.call, concurrent.WithRetry(concurrent.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Jitter:         0.5,
+	}))
+
+	result, err := stage.Transform(context.Background(), []int{1, 2, 3})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result) != 3 || result[0] != 1 || result[1] != 2 || result[2] != 3 {
+		t.Errorf("Unexpected result: %v", result)
+	}
+	for _, value := range []int{1, 2, 3} {
+		if attempts := flaky.attempts(value); attempts != 3 {
+			t.Errorf("Expected 3 attempts for %d, got %d", value, attempts)
+		}
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	flaky := newFlakyAction(5)
+	stage := concurrent.NewStage(flaky.call, concurrent.WithRetry(concurrent.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+	}))
+
+	_, err := stage.Transform(context.Background(), []int{1})
+
+	var retryErr *concurrent.RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("Expected *RetryError, got: %v", err)
+	}
+	if retryErr.Attempts != 3 || !errors.Is(err, errFlaky) {
+		t.Errorf("Unexpected retry error: %v", retryErr)
+	}
+	if attempts := flaky.attempts(1); attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryManyAttemptsKeepsMaxBackoff(t *testing.T) {
+	const attempts = 40
+	var mu sync.Mutex
+	var calls []time.Time
+	stage := concurrent.NewStage(func(int) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, time.Now())
+		return 0, errFlaky
+	}, concurrent.WithRetry(concurrent.RetryPolicy{
+		MaxAttempts:    attempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		Multiplier:     10,
+	}))
+
+	_, err := stage.Transform(context.Background(), []int{1})
+
+	var retryErr *concurrent.RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != attempts {
+		t.Fatalf("Expected %d attempts, got: %v", attempts, err)
+	}
+	// Without a cap, the backoff overflows after a dozen retries and stops being waited.
+	for i := 2; i < len(calls); i++ {
+		if wait := calls[i].Sub(calls[i-1]); wait < 2*time.Millisecond {
+			t.Fatalf("Expected a wait of at least 2ms before attempt %d, got %v", i+1, wait)
+		}
+	}
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	flaky := newFlakyAction(5)
+	stage := concurrent.NewStage(flaky.call, concurrent.WithRetry(concurrent.RetryPolicy{
+		MaxAttempts: 3,
+		Retryable: func(err error) bool {
+			return !errors.Is(err, errFlaky)
+		},
+	}))
+
+	_, err := stage.Transform(context.Background(), []int{1})
+
+	var retryErr *concurrent.RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 1 {
+		t.Errorf("Expected a single attempt, got: %v", err)
+	}
+}
+
+func TestRetryAttemptTimeout(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	stage := concurrent.NewStageContext(func(ctx context.Context, value int) (int, error) {
+		mu.Lock()
+		attempts++
+		first := attempts == 1
+		mu.Unlock()
+		if first {
+			// The first attempt hangs until its context is cancelled.
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+		return value * 10, nil
+	}, concurrent.WithRetry(concurrent.RetryPolicy{
+		MaxAttempts:    2,
+		AttemptTimeout: 10 * time.Millisecond,
+	}))
+
+	result, err := stage.Transform(context.Background(), []int{4})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result) != 1 || result[0] != 40 {
+		t.Errorf("Unexpected result: %v", result)
+	}
+}
+
+func TestRetryContextCancelDuringBackoff(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	stage := concurrent.NewStage(func(int) (int, error) {
+		cancel()
+		return 0, errFlaky
+	}, concurrent.WithRetry(concurrent.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Hour,
+	}))
+
+	start := time.Now()
+	_, err := stage.Transform(ctx, []int{1})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the backoff to be interrupted, took %v", elapsed)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+var errFlaky = errors.New("flaky")
+
+// flakyAction fails the first failures calls for each value and then returns the value.
+type flakyAction struct {
+	failures int
+	mu       sync.Mutex
+	calls    map[int]int
+}
+
+func newFlakyAction(failures int) *flakyAction {
+	return &flakyAction{failures: failures, calls: make(map[int]int)}
+}
+
+func (f *flakyAction) call(value int) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[value]++
+	if f.calls[value] <= f.failures {
+		return 0, errFlaky
+	}
+	return value, nil
+}
+
+func (f *flakyAction) attempts(value int) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[value]
+}
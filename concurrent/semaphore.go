@@ -0,0 +1,167 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// ErrWeightTooLarge is returned when acquiring a weight larger than the capacity of a semaphore,
+// which could never be satisfied.
+var ErrWeightTooLarge = errors.New("weight exceeds the semaphore capacity")
+
+// Semaphore is a weighted semaphore, which bounds the total weight of the work running at the same
+// time. Waiters are served in the order they arrived, so a heavy item is not starved by a stream of
+// light ones. A Semaphore is safe for concurrent use, and may be shared by several stages.
+type Semaphore struct {
+	capacity int64
+
+	mu      sync.Mutex
+	used    int64
+	waiters []*semaphoreWaiter
+}
+
+type semaphoreWaiter struct {
+	weight int64
+	ready  chan struct{}
+}
+
+// NewSemaphore returns a Semaphore with the given total capacity.
+func NewSemaphore(capacity int64) *Semaphore {
+	return &Semaphore{capacity: capacity}
+}
+
+// Acquire blocks until the weight is available and acquires it. It returns the context's error,
+// without acquiring anything, if ctx is done first, or ErrWeightTooLarge if the weight exceeds the
+// capacity of the semaphore.
+func (s *Semaphore) Acquire(ctx context.Context, weight int64) error {
+	if weight > s.capacity {
+		return ErrWeightTooLarge
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if len(s.waiters) == 0 && s.used+weight <= s.capacity {
+		s.used += weight
+		s.mu.Unlock()
+		return nil
+	}
+	waiter := &semaphoreWaiter{weight: weight, ready: make(chan struct{})}
+	s.waiters = append(s.waiters, waiter)
+	s.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		select {
+		case <-waiter.ready:
+			// The weight was acquired while the context was being cancelled; give it back.
+			s.used -= weight
+		default:
+			s.waiters = slices.DeleteFunc(s.waiters, func(w *semaphoreWaiter) bool {
+				return w == waiter
+			})
+		}
+		s.notify()
+		return ctx.Err()
+	}
+}
+
+// TryAcquire acquires the weight without blocking, and reports whether it succeeded.
+func (s *Semaphore) TryAcquire(weight int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.waiters) > 0 || s.used+weight > s.capacity {
+		return false
+	}
+	s.used += weight
+	return true
+}
+
+// Release releases the weight, waking up the waiters that now fit. It panics if more weight is
+// released than is held.
+func (s *Semaphore) Release(weight int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.used -= weight
+	if s.used < 0 {
+		panic("semaphore released more weight than held")
+	}
+	s.notify()
+}
+
+// notify hands the available capacity to the waiters in arrival order, stopping at the first one
+// that does not fit. It must be called with the lock held.
+func (s *Semaphore) notify() {
+	for len(s.waiters) > 0 {
+		waiter := s.waiters[0]
+		if s.used+waiter.weight > s.capacity {
+			return
+		}
+		s.used += waiter.weight
+		close(waiter.ready)
+		s.waiters = s.waiters[1:]
+	}
+}
+
+// WithSemaphore makes every call to the stage's action, including each retry, hold the weight of
+// its input item in the semaphore. A nil weight function gives every item a weight of 1, and
+// negative weights are treated as 0. The stage's input type must be In, otherwise NewStage panics;
+// items heavier than the capacity of the semaphore fail with ErrWeightTooLarge.
+func WithSemaphore[In any](semaphore *Semaphore, weight func(In) int64) StageOption {
+	return func(config *stageConfig) {
+		config.semaphore = semaphore
+		// A nil function of any type must not be checked against the input type of the stage.
+		config.weight = nil
+		if weight != nil {
+			config.weight = weight
+		}
+	}
+}
+
+// throttle wraps the action so that every call waits for the semaphore and the rate limiter of
+// the stage, if any.
+func throttle[In any, Out any](action TransformActionContext[In, Out], config stageConfig) TransformActionContext[In, Out] {
+	weight := func(In) int64 { return 1 }
+	if config.weight != nil {
+		fn, ok := config.weight.(func(In) int64)
+		if !ok {
+			panic("semaphore weight function must take the stage's input type")
+		}
+		weight = fn
+	}
+	return func(ctx context.Context, value In) (output Out, err error) {
+		if config.semaphore != nil {
+			w := max(weight(value), 0)
+			if err := config.semaphore.Acquire(ctx, w); err != nil {
+				return output, err
+			}
+			defer config.semaphore.Release(w)
+		}
+		if config.limiter != nil {
+			if err := config.limiter.Wait(ctx); err != nil {
+				return output, err
+			}
+		}
+		return action(ctx, value)
+	}
+}
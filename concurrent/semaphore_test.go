@@ -0,0 +1,161 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+func TestSemaphore(t *testing.T) {
+	sem := concurrent.NewSemaphore(5)
+
+	if err := sem.Acquire(context.Background(), 3); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sem.TryAcquire(3) {
+		t.Errorf("Expected TryAcquire to fail beyond the capacity")
+	}
+	if !sem.TryAcquire(2) {
+		t.Errorf("Expected TryAcquire to succeed within the capacity")
+	}
+	if err := sem.Acquire(context.Background(), 6); !errors.Is(err, concurrent.ErrWeightTooLarge) {
+		t.Errorf("Expected %v, got %v", concurrent.ErrWeightTooLarge, err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		defer close(acquired)
+		if err := sem.Acquire(context.Background(), 4); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}()
+	sem.Release(2)
+	select {
+	case <-acquired:
+		t.Fatalf("Expected Acquire to wait for enough capacity")
+	case <-time.After(10 * time.Millisecond):
+	}
+	sem.Release(3)
+	<-acquired
+}
+
+func TestSemaphoreFIFO(t *testing.T) {
+	sem := concurrent.NewSemaphore(4)
+	if err := sem.Acquire(context.Background(), 4); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A heavy waiter must not be overtaken by a light one that arrived later.
+	heavy := make(chan struct{})
+	go func() {
+		defer close(heavy)
+		if err := sem.Acquire(context.Background(), 4); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if sem.TryAcquire(1) {
+		t.Errorf("Expected TryAcquire to fail while a waiter is queued")
+	}
+	sem.Release(4)
+	<-heavy
+	sem.Release(4)
+}
+
+func TestSemaphoreContextCancel(t *testing.T) {
+	sem := concurrent.NewSemaphore(1)
+	if err := sem.Acquire(context.Background(), 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := sem.Acquire(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	sem.Release(1)
+	if !sem.TryAcquire(1) {
+		t.Errorf("Expected the cancelled waiter to leave the capacity available")
+	}
+}
+
+func TestStageWithSemaphore(t *testing.T) {
+	sem := concurrent.NewSemaphore(10)
+	var used, peak atomic.Int64
+	stage := concurrent.NewStage(func(s string) (int, error) {
+		n := used.Add(int64(len(s)))
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		used.Add(-int64(len(s)))
+		return len(s), nil
+	}, concurrent.WithWorkers(8), concurrent.WithSemaphore(sem, func(s string) int64 { return int64(len(s)) }))
+
+	items := []string{"aaaaaa", "bb", "cccc", "dddddddddd", "e", "fff", "gggggggg", "hh"}
+	result, err := stage.Transform(context.Background(), items)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result) != len(items) {
+		t.Errorf("Expected %d outputs, got %d", len(items), len(result))
+	}
+	if p := peak.Load(); p > 10 {
+		t.Errorf("Expected the weight in flight to stay within 10, got %d", p)
+	}
+}
+
+func TestStageWithSemaphoreTooHeavy(t *testing.T) {
+	sem := concurrent.NewSemaphore(2)
+	stage := concurrent.NewStage(length, concurrent.WithSemaphore(sem, func(s string) int64 { return int64(len(s)) }))
+
+	_, err := stage.Transform(context.Background(), []string{"a", "bbb"})
+
+	if !errors.Is(err, concurrent.ErrWeightTooLarge) {
+		t.Errorf("Expected %v, got %v", concurrent.ErrWeightTooLarge, err)
+	}
+}
+
+func TestStageWithSemaphoreWrongType(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic")
+		}
+	}()
+	concurrent.NewStage(length, concurrent.WithSemaphore(concurrent.NewSemaphore(1), func(int) int64 { return 1 }))
+}
+
+func TestStageWithSemaphoreNilWeight(t *testing.T) {
+	sem := concurrent.NewSemaphore(1)
+	stage := concurrent.NewStage(length, concurrent.WithSemaphore[int](sem, nil))
+
+	// Every item has a weight of 1, whatever the type the nil weight function was given.
+	result, err := stage.Transform(context.Background(), []string{"a", "bbb"})
+
+	if err != nil || !slices.Equal(result, []int{1, 3}) {
+		t.Errorf("Expected [1 3], got %v, %v", result, err)
+	}
+}
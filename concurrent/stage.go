@@ -0,0 +1,288 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Stage is a typed step of a concurrent pipeline that transforms items of type In into items of
+// type Out. Stages are created with NewStage and composed with Then, so that each stage of a
+// pipeline may change the element type while the compiler checks that adjacent stages fit
+// together. A Stage is immutable and can be run any number of times, concurrently.
+type Stage[In any, Out any] struct {
+	// steps is the number of stages created with NewStage that make up this stage.
+	steps int
+	// run starts the stage's goroutines, with step being the position of its first step in the
+	// pipeline.
+	run func(p *pipeline, step int, in <-chan item[In]) <-chan item[Out]
+}
+
+// NewStage returns a Stage that applies the action on its input items concurrently, as configured
+// by the options. Failed items are handled according to the error policy of the run.
+func NewStage[In any, Out any](action TransformActionWithError[In, Out], opts ...StageOption) Stage[In, Out] {
+	return NewStageContext(func(_ context.Context, value In) (Out, error) {
+		return action(value)
+	}, opts...)
+}
+
+// NewStageContext is like NewStage, but the action receives a context that is done when the run
+// is cancelled or, with a retry policy, when an attempt times out.
+func NewStageContext[In any, Out any](action TransformActionContext[In, Out], opts ...StageOption) Stage[In, Out] {
+	config := newStageConfig(opts)
+	action = decorate(action, config)
+	return Stage[In, Out]{
+		steps: 1,
+		run: func(p *pipeline, step int, in <-chan item[In]) <-chan item[Out] {
+			out := make(chan item[Out], config.buffer)
+			process(p, config.workers, in,
+				func(worker, queue int, input item[In]) (Out, error) {
+					return observe(p, step, worker, queue, action, input)
+				},
+				func(input item[In], output Out, err error) bool {
+					if err != nil {
+						p.fail(failed(p, step, input, err))
+						return true
+					}
+					return send(p.ctx, out, item[Out]{index: input.index, value: output})
+				},
+				func() { close(out) },
+			)
+			return out
+		},
+	}
+}
+
+// decorate wraps the action of a stage with its throttling and retry policy, if any.
+func decorate[In any, Out any](action TransformActionContext[In, Out], config stageConfig) TransformActionContext[In, Out] {
+	if config.semaphore != nil || config.limiter != nil {
+		action = throttle(action, config)
+	}
+	if config.retry != nil {
+		policy := config.retry
+		inner := action
+		action = func(ctx context.Context, value In) (Out, error) {
+			return retry(ctx, policy, inner, value)
+		}
+	}
+	return action
+}
+
+// process starts the workers of a stage, which compute every unit of work received from in and
+// hand the result to finish, until in is closed or finish reports false. Units are items for
+// stages created with NewStage, and batches of items for those created with NewBatchStage. The
+// workers run on the pool of the run, if any, and done is called once all of them have exited.
+func process[U any, R any](p *pipeline, workers int, in <-chan U, compute func(worker, queue int, unit U) (R, error), finish func(unit U, result R, err error) bool, done func()) {
+	if p.config.pool != nil {
+		p.goroutine(func() {
+			defer done()
+			dispatch(p, workers, in, compute, finish)
+		})
+		return
+	}
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		p.goroutine(func() {
+			defer wg.Done()
+			for unit := range receive(p.ctx, in) {
+				result, err := compute(worker, len(in), unit)
+				if !finish(unit, result, err) {
+					return
+				}
+			}
+		})
+	}
+	p.goroutine(func() {
+		wg.Wait()
+		done()
+	})
+}
+
+// Then composes two stages into one that feeds the output items of first into second.
+func Then[A any, B any, C any](first Stage[A, B], second Stage[B, C]) Stage[A, C] {
+	return Stage[A, C]{
+		steps: first.steps + second.steps,
+		run: func(p *pipeline, step int, in <-chan item[A]) <-chan item[C] {
+			return second.run(p, step+first.steps, first.run(p, step, in))
+		},
+	}
+}
+
+// Transform runs the stage on the input items concurrently, preserving the order of input items
+// in the output. How failed items are reported depends on the error policy, FailFast by default;
+// if ctx is done, the processing is halted and the context's error is returned. The call returns
+// once every goroutine it started has exited, which includes waiting for the actions that were
+// already running to return.
+func (s Stage[In, Out]) Transform(ctx context.Context, items []In, opts ...Option) ([]Out, error) {
+	config := newConfig(opts)
+	p := newPipeline(ctx, config)
+	defer p.cancel()
+	p.input = func(index int) any {
+		return items[index]
+	}
+
+	// Send the items to the first stage along with their indices.
+	in := make(chan item[In])
+	p.goroutine(func() {
+		defer close(in)
+		for i, value := range items {
+			if !send(p.ctx, in, item[In]{index: i, value: value}) {
+				return
+			}
+		}
+	})
+
+	// Collect the results and maintain the input order.
+	outputs := make([]Out, len(items))
+	succeeded := make([]bool, len(items))
+	for output := range s.run(p, 0, in) {
+		outputs[output.index] = output.value
+		succeeded[output.index] = true
+	}
+	p.wg.Wait()
+
+	err := p.err()
+	switch {
+	case err != nil && config.errorPolicy == CollectAll && p.ctx.Err() == nil:
+		return outputs, err
+	case err != nil:
+		return nil, err
+	case config.errorPolicy == Skip:
+		kept := outputs[:0]
+		for i, output := range outputs {
+			if succeeded[i] {
+				kept = append(kept, output)
+			}
+		}
+		return kept, nil
+	}
+	return outputs, nil
+}
+
+// TransformChannels runs the stage on the items received from the input channel concurrently,
+// not guaranteeing the order of input items in the output channel unless the WithOrdered option
+// is used. If ctx is done, the processing is halted and the context's error is sent to the error
+// channel. Failed items are reported according to the error policy, FailFast by default:
+//
+//   - FailFast stops reading input items on the first failure and sends it to the error channel,
+//     while the items that were already being processed are still sent to the output channel.
+//     Once the failure has been received, the outputs that cannot be sent right away are
+//     discarded and the run is halted, so reading only the error channel is enough for every
+//     goroutine to exit.
+//   - CollectAll sends every failure to the error channel as it happens, so both channels must be
+//     read concurrently.
+//   - Skip drops failed items without reporting them, except for panics, which are handled as
+//     under FailFast.
+//
+// Both channels are closed after every goroutine started by the call has exited.
+func (s Stage[In, Out]) TransformChannels(ctx context.Context, items <-chan In, opts ...Option) (<-chan Out, <-chan error) {
+	config := newConfig(opts)
+	errs := make(chan error, 1)
+	p := newPipeline(ctx, config)
+	p.graceful = true
+	p.report = func(err error) {
+		send(p.ctx, errs, err)
+	}
+	p.received = func() bool {
+		return len(errs) == 0
+	}
+	var reorder *reorderBuffer[Out]
+	if config.window > 0 {
+		reorder = newReorderBuffer[Out](p, config.window)
+	}
+
+	in := make(chan item[In])
+	p.goroutine(func() {
+		defer close(in)
+		index := 0
+		for value := range receive(p.feed, items) {
+			if reorder != nil && !reorder.acquire(p) {
+				return
+			}
+			if !send(p.feed, in, item[In]{index: index, value: value}) {
+				return
+			}
+			index++
+		}
+	})
+
+	transformed := s.run(p, 0, in)
+	outputs := make(chan Out)
+	go func() {
+		defer close(errs)
+		defer close(outputs)
+		defer p.cancel()
+		if reorder != nil {
+			reorder.forward(p, transformed, outputs)
+		} else {
+			for output := range transformed {
+				if !deliver(p, outputs, output.value) {
+					break
+				}
+			}
+		}
+		drain(transformed)
+		p.wg.Wait()
+		if p.failure == nil && p.ctx.Err() != nil {
+			errs <- context.Cause(p.ctx)
+		}
+	}()
+
+	return outputs, errs
+}
+
+// abandonCheckInterval is how often a channel run that stopped feeding items on a failure checks
+// whether the caller received the failure while an output is waiting to be sent.
+const abandonCheckInterval = 10 * time.Millisecond
+
+// deliver sends value to the output channel of a channel run. Once the run stopped feeding items
+// on a failure and the caller received it, the caller may have stopped reading the output channel,
+// so if value cannot be sent right away the run is halted instead. It reports whether the value
+// was sent.
+func deliver[T any](p *pipeline, outputs chan<- T, value T) bool {
+	select {
+	case outputs <- value:
+		return true
+	case <-p.feed.Done():
+	}
+	var ticker *time.Ticker
+	for {
+		select {
+		case outputs <- value:
+			return true
+		case <-p.ctx.Done():
+			return false
+		default:
+		}
+		if p.received() {
+			p.cancel()
+			return false
+		}
+		if ticker == nil {
+			ticker = time.NewTicker(abandonCheckInterval)
+			defer ticker.Stop()
+		}
+		select {
+		case outputs <- value:
+			return true
+		case <-p.ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+func TestStageTransform(t *testing.T) {
+	half := func(n int) (float64, error) { return float64(n) / 2, nil }
+	format := func(f float64) (string, error) { return strconv.FormatFloat(f, 'f', 1, 64), nil }
+	stage := concurrent.Then(
+		concurrent.Then(
+			concurrent.NewStage(length, concurrent.WithWorkers(3)),
+			concurrent.NewStage(half, concurrent.WithWorkers(2)),
+		),
+		concurrent.NewStage(format, concurrent.WithWorkers(4)),
+	)
+
+	result, err := stage.Transform(context.Background(), []string{"a", "bb", "ccc", "dddd"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"0.5", "1.0", "1.5", "2.0"}
+	if strings.Join(result, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestStageTransformEmpty(t *testing.T) {
+	stage := concurrent.NewStage(length, concurrent.WithWorkers(3))
+
+	result, err := stage.Transform(context.Background(), nil)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(result) != 0 {
+		t.Errorf("Expected empty result, got %v", result)
+	}
+}
+
+func TestStageTransformError(t *testing.T) {
+	before := runtime.NumGoroutine()
+	failure := errors.New("odd length")
+	stage := concurrent.Then(
+		concurrent.NewStage(length, concurrent.WithWorkers(2)),
+		concurrent.NewStage(func(n int) (int, error) {
+			if n%2 == 1 {
+				return 0, failure
+			}
+			return n, nil
+		}, concurrent.WithWorkers(2)),
+	)
+
+	result, err := stage.Transform(context.Background(), []string{"aa", "b", "cccc"})
+
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected %v, got %v", failure, err)
+	}
+	if result != nil {
+		t.Errorf("Expected nil result, got %v", result)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestStageTransformContextCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := concurrent.NewStage(length, concurrent.WithWorkers(2)).Transform(ctx, []string{"a", "b"})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestStageTransformChannels(t *testing.T) {
+	repeat := func(n int) (string, error) { return strings.Repeat("x", n*2), nil }
+	stage := concurrent.Then(
+		concurrent.NewStage(length, concurrent.WithWorkers(2)),
+		concurrent.NewStage(repeat, concurrent.WithWorkers(3)),
+	)
+
+	inputChan := make(chan string, 3)
+	inputChan <- "a"
+	inputChan <- "bb"
+	inputChan <- "ccc"
+	close(inputChan)
+
+	outputChan, errChan := stage.TransformChannels(context.Background(), inputChan)
+
+	var outputs []string
+	for output := range outputChan {
+		outputs = append(outputs, output)
+	}
+	if err := <-errChan; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	sort.Strings(outputs)
+	expected := []string{"xx", "xxxx", "xxxxxx"}
+	if strings.Join(outputs, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, outputs)
+	}
+}
+
+func TestStageTransformChannelsError(t *testing.T) {
+	before := runtime.NumGoroutine()
+	failure := errors.New("failed")
+	stage := concurrent.NewStage(func(n int) (int, error) {
+		if n == 3 {
+			return 0, failure
+		}
+		return n, nil
+	}, concurrent.WithWorkers(2))
+
+	// An input channel that is never closed; the failure must stop the reading.
+	inputChan := make(chan int)
+	done := make(chan struct{})
+	producerDone := make(chan struct{})
+	go func() {
+		defer close(producerDone)
+		for i := 0; ; i++ {
+			select {
+			case inputChan <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	outputChan, errChan := stage.TransformChannels(context.Background(), inputChan)
+
+	for range outputChan {
+	}
+	if err := <-errChan; !errors.Is(err, failure) {
+		t.Errorf("Expected %v, got %v", failure, err)
+	}
+
+	close(done)
+	<-producerDone
+	assertNoGoroutineLeak(t, before)
+}
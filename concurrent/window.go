@@ -0,0 +1,361 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Window is a span of event time, including Start and excluding End.
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// WindowResult is the aggregate of the items of a window.
+type WindowResult[A any] struct {
+	Window Window
+	Value  A
+	// Late reports whether the result is an update of a window that was already emitted, caused
+	// by an item that arrived after the watermark passed the end of the window but within the
+	// allowed lateness.
+	Late bool
+}
+
+// WindowOption configures a window operator.
+type WindowOption func(*windowConfig)
+
+type windowConfig struct {
+	clock    Clock
+	delay    time.Duration
+	lateness time.Duration
+	idle     time.Duration
+	// dropped is the func(T) passed to WithDroppedItems, checked against the item type by the
+	// window operator.
+	dropped any
+}
+
+// WithWatermarkDelay makes the watermark trail the latest event time seen by the delay, which
+// bounds how out of order items may arrive before they are considered late. The default is 0.
+func WithWatermarkDelay(delay time.Duration) WindowOption {
+	return func(config *windowConfig) {
+		config.delay = delay
+	}
+}
+
+// WithAllowedLateness keeps the windows open for the given duration of event time after the
+// watermark passed their end. Items arriving in that period update the window, which is emitted
+// again with Late set; later items are dropped. The default is 0, which drops every late item.
+func WithAllowedLateness(lateness time.Duration) WindowOption {
+	return func(config *windowConfig) {
+		config.lateness = lateness
+	}
+}
+
+// WithIdleTimeout makes the watermark advance with the processing time once no item arrived for
+// the timeout, as if the event time kept pace with the clock. Without it, the watermark only
+// advances when items arrive, so the last windows of a quiet stream are only emitted when the
+// input channel is closed. The clock is checked once per timeout, so the watermark may start
+// advancing up to twice the timeout after the last item.
+func WithIdleTimeout(timeout time.Duration) WindowOption {
+	return func(config *windowConfig) {
+		config.idle = timeout
+	}
+}
+
+// WithClock sets the clock used to measure the idle timeout. The default is SystemClock.
+func WithClock(clock Clock) WindowOption {
+	return func(config *windowConfig) {
+		config.clock = clock
+	}
+}
+
+// WithDroppedItems calls fn with every item that is dropped for being too late. The item type of
+// the window operator must be T, otherwise it panics.
+func WithDroppedItems[T any](fn func(T)) WindowOption {
+	return func(config *windowConfig) {
+		// A nil function of any type must not be checked against the item type.
+		config.dropped = nil
+		if fn != nil {
+			config.dropped = fn
+		}
+	}
+}
+
+// TumblingWindows aggregates the items received from the input channel into consecutive windows
+// of the given size, aligned on multiples of size since the zero time, so that every item belongs
+// to exactly one window.
+//
+// Items are assigned to windows by their event time. The watermark tracks how far the event time
+// of the stream is believed to be complete: it trails the latest event time seen by the watermark
+// delay. Once the watermark passes the end of a window, the aggregate of its items, in arrival
+// order, is sent to the returned channel; windows are emitted in order of their end. Items of
+// windows the watermark passed by more than the allowed lateness are dropped. When the input
+// channel is closed, the remaining windows are emitted and the returned channel is closed. If ctx
+// is done, the returned channel is closed without emitting the remaining windows.
+func TumblingWindows[T any, A any](ctx context.Context, items <-chan T, size time.Duration, eventTime func(T) time.Time, aggregate func([]T) A, opts ...WindowOption) <-chan WindowResult[A] {
+	if size <= 0 {
+		panic("tumbling windows require a positive size")
+	}
+	return windowing(ctx, items, eventTime, aggregate, opts, func(t time.Time) []Window {
+		start := t.Truncate(size)
+		return []Window{{Start: start, End: start.Add(size)}}
+	}, false)
+}
+
+// SlidingWindows aggregates the items received from the input channel into windows of the given
+// size that start every slide, aligned on multiples of slide since the zero time, so that every
+// item belongs to about size/slide windows. Windows are emitted like in TumblingWindows.
+func SlidingWindows[T any, A any](ctx context.Context, items <-chan T, size, slide time.Duration, eventTime func(T) time.Time, aggregate func([]T) A, opts ...WindowOption) <-chan WindowResult[A] {
+	if size <= 0 || slide <= 0 {
+		panic("sliding windows require a positive size and slide")
+	}
+	return windowing(ctx, items, eventTime, aggregate, opts, func(t time.Time) []Window {
+		var windows []Window
+		for start := t.Truncate(slide); start.Add(size).After(t); start = start.Add(-slide) {
+			windows = append(windows, Window{Start: start, End: start.Add(size)})
+		}
+		slices.Reverse(windows)
+		return windows
+	}, false)
+}
+
+// SessionWindows aggregates the items received from the input channel into sessions: windows of
+// activity that close once no item arrived for the given gap of event time. Each item opens a
+// window from its event time to its event time plus gap, and overlapping windows are merged, so a
+// late item may join two sessions into one. Windows are emitted like in TumblingWindows; a session
+// that merged sessions that were already emitted is emitted with Late set.
+func SessionWindows[T any, A any](ctx context.Context, items <-chan T, gap time.Duration, eventTime func(T) time.Time, aggregate func([]T) A, opts ...WindowOption) <-chan WindowResult[A] {
+	if gap <= 0 {
+		panic("session windows require a positive gap")
+	}
+	return windowing(ctx, items, eventTime, aggregate, opts, func(t time.Time) []Window {
+		return []Window{{Start: t, End: t.Add(gap)}}
+	}, true)
+}
+
+// windowing starts a window operator that assigns every item to the windows returned by assign,
+// merging the overlapping windows if merging is set.
+func windowing[T any, A any](ctx context.Context, items <-chan T, eventTime func(T) time.Time, aggregate func([]T) A, opts []WindowOption, assign func(time.Time) []Window, merging bool) <-chan WindowResult[A] {
+	config := windowConfig{clock: SystemClock}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	op := &windowOperator[T, A]{
+		config:    config,
+		eventTime: eventTime,
+		aggregate: aggregate,
+		assign:    assign,
+		merging:   merging,
+	}
+	if config.dropped != nil {
+		dropped, ok := config.dropped.(func(T))
+		if !ok {
+			panic("dropped items function must take the item type of the window operator")
+		}
+		op.dropped = dropped
+	}
+	out := make(chan WindowResult[A])
+	go func() {
+		defer close(out)
+		op.run(ctx, items, out)
+	}()
+	return out
+}
+
+type windowOperator[T any, A any] struct {
+	config    windowConfig
+	eventTime func(T) time.Time
+	aggregate func([]T) A
+	assign    func(time.Time) []Window
+	merging   bool
+	dropped   func(T)
+
+	// windows holds the windows that were not purged yet.
+	windows []*windowState[T]
+	// watermark is the event time up to which the stream is believed to be complete.
+	watermark time.Time
+	// latest is the latest event time seen, and arrival the processing time it arrived at.
+	latest  time.Time
+	arrival time.Time
+	// received is the processing time the last item arrived at.
+	received time.Time
+}
+
+type windowState[T any] struct {
+	window Window
+	items  []T
+	// emitted reports whether the window was emitted since the watermark passed its end.
+	emitted bool
+	// revision reports whether a result covering items of the window was already emitted, which
+	// makes the next results of the window late.
+	revision bool
+}
+
+// run processes the items until the input channel is closed or ctx is done.
+func (op *windowOperator[T, A]) run(ctx context.Context, items <-chan T, out chan<- WindowResult[A]) {
+	// The idle timeout is checked periodically, as often as the timeout itself.
+	var idle <-chan time.Time
+	if op.config.idle > 0 {
+		idle = op.config.clock.After(op.config.idle)
+	}
+	for {
+		select {
+		case value, ok := <-items:
+			if !ok {
+				op.flush(ctx, out)
+				return
+			}
+			op.received = op.config.clock.Now()
+			t := op.eventTime(value)
+			if t.After(op.latest) {
+				op.latest = t
+				op.arrival = op.received
+			}
+			if !op.add(ctx, out, value, t) || !op.advance(ctx, out, op.latest.Add(-op.config.delay)) {
+				return
+			}
+		case <-idle:
+			idle = op.config.clock.After(op.config.idle)
+			now := op.config.clock.Now()
+			if op.latest.IsZero() || now.Sub(op.received) < op.config.idle {
+				continue
+			}
+			if !op.advance(ctx, out, op.latest.Add(now.Sub(op.arrival)-op.config.delay)) {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// add assigns the item to its windows, emitting the windows that were already emitted again. It
+// reports false if ctx is done.
+func (op *windowOperator[T, A]) add(ctx context.Context, out chan<- WindowResult[A], value T, t time.Time) bool {
+	added := false
+	for _, window := range op.assign(t) {
+		state := op.state(window)
+		if state == nil {
+			continue
+		}
+		state.items = append(state.items, value)
+		added = true
+		if state.emitted && !op.emit(ctx, out, state) {
+			return false
+		}
+	}
+	if !added && op.dropped != nil {
+		op.dropped(value)
+	}
+	return true
+}
+
+// state returns the state of the window, creating it or, for sessions, merging it with the
+// overlapping windows. It returns nil if the window is too late to be kept.
+func (op *windowOperator[T, A]) state(window Window) *windowState[T] {
+	if !op.merging {
+		for _, state := range op.windows {
+			if state.window == window {
+				return state
+			}
+		}
+		if op.expired(window) {
+			return nil
+		}
+		state := &windowState[T]{window: window}
+		op.windows = append(op.windows, state)
+		return state
+	}
+
+	merged := &windowState[T]{window: window}
+	overlapping := false
+	op.windows = slices.DeleteFunc(op.windows, func(state *windowState[T]) bool {
+		if !state.window.Start.Before(window.End) || !window.Start.Before(state.window.End) {
+			return false
+		}
+		overlapping = true
+		merged.items = append(merged.items, state.items...)
+		merged.revision = merged.revision || state.revision
+		if state.window.Start.Before(merged.window.Start) {
+			merged.window.Start = state.window.Start
+		}
+		if state.window.End.After(merged.window.End) {
+			merged.window.End = state.window.End
+		}
+		return true
+	})
+	if !overlapping && op.expired(window) {
+		return nil
+	}
+	// A session extended past the watermark waits for the watermark again.
+	merged.emitted = merged.revision && !merged.window.End.After(op.watermark)
+	op.windows = append(op.windows, merged)
+	return merged
+}
+
+// expired reports whether the watermark passed the end of the window by more than the allowed
+// lateness.
+func (op *windowOperator[T, A]) expired(window Window) bool {
+	return !window.End.Add(op.config.lateness).After(op.watermark)
+}
+
+// advance moves the watermark forward, emitting the windows it passed and purging the expired
+// ones. It reports false if ctx is done.
+func (op *windowOperator[T, A]) advance(ctx context.Context, out chan<- WindowResult[A], watermark time.Time) bool {
+	if !watermark.After(op.watermark) {
+		return true
+	}
+	op.watermark = watermark
+	op.sort()
+	for _, state := range op.windows {
+		if !state.emitted && !state.window.End.After(op.watermark) && !op.emit(ctx, out, state) {
+			return false
+		}
+	}
+	op.windows = slices.DeleteFunc(op.windows, func(state *windowState[T]) bool {
+		return op.expired(state.window)
+	})
+	return true
+}
+
+// flush emits every window that was not emitted yet.
+func (op *windowOperator[T, A]) flush(ctx context.Context, out chan<- WindowResult[A]) {
+	op.sort()
+	for _, state := range op.windows {
+		if !state.emitted && !op.emit(ctx, out, state) {
+			return
+		}
+	}
+}
+
+// emit sends the aggregate of the window. It reports false if ctx is done.
+func (op *windowOperator[T, A]) emit(ctx context.Context, out chan<- WindowResult[A], state *windowState[T]) bool {
+	result := WindowResult[A]{Window: state.window, Value: op.aggregate(state.items), Late: state.revision}
+	state.emitted, state.revision = true, true
+	return send(ctx, out, result)
+}
+
+// sort orders the windows by end, then by start.
+func (op *windowOperator[T, A]) sort() {
+	slices.SortFunc(op.windows, func(a, b *windowState[T]) int {
+		if c := a.window.End.Compare(b.window.End); c != 0 {
+			return c
+		}
+		return a.window.Start.Compare(b.window.Start)
+	})
+}
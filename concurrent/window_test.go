@@ -0,0 +1,290 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrent_test
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/f0rmiga/datanalgo/concurrent"
+)
+
+// epoch is the start of the event time of the window tests.
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// reading is a value observed at an event time.
+type reading struct {
+	at    time.Duration
+	value int
+}
+
+func readingTime(r reading) time.Time {
+	return epoch.Add(r.at)
+}
+
+func sumReadings(readings []reading) int {
+	total := 0
+	for _, r := range readings {
+		total += r.value
+	}
+	return total
+}
+
+// result is the expected result of a window, relative to the epoch.
+type result struct {
+	start, end time.Duration
+	value      int
+	late       bool
+}
+
+// streamReadings returns a channel that receives the readings and is then closed.
+func streamReadings(readings ...reading) <-chan reading {
+	ch := make(chan reading)
+	go func() {
+		defer close(ch)
+		for _, r := range readings {
+			ch <- r
+		}
+	}()
+	return ch
+}
+
+func assertWindows(t *testing.T, results <-chan concurrent.WindowResult[int], expected []result) {
+	t.Helper()
+	var got []result
+	for r := range results {
+		got = append(got, result{
+			start: r.Window.Start.Sub(epoch),
+			end:   r.Window.End.Sub(epoch),
+			value: r.Value,
+			late:  r.Late,
+		})
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestTumblingWindows(t *testing.T) {
+	before := runtime.NumGoroutine()
+	items := streamReadings(
+		reading{10 * time.Second, 1},
+		reading{50 * time.Second, 2},
+		reading{65 * time.Second, 3},
+		reading{150 * time.Second, 4},
+	)
+
+	results := concurrent.TumblingWindows(context.Background(), items, time.Minute, readingTime, sumReadings)
+
+	assertWindows(t, results, []result{
+		{0, time.Minute, 3, false},
+		{time.Minute, 2 * time.Minute, 3, false},
+		{2 * time.Minute, 3 * time.Minute, 4, false},
+	})
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestTumblingWindowsWatermarkDelay(t *testing.T) {
+	items := streamReadings(
+		reading{10 * time.Second, 1},
+		reading{65 * time.Second, 2},
+		// Out of order, but within the watermark delay.
+		reading{50 * time.Second, 3},
+		reading{100 * time.Second, 1},
+	)
+
+	results := concurrent.TumblingWindows(context.Background(), items, time.Minute, readingTime, sumReadings,
+		concurrent.WithWatermarkDelay(30*time.Second))
+
+	assertWindows(t, results, []result{
+		{0, time.Minute, 4, false},
+		{time.Minute, 2 * time.Minute, 3, false},
+	})
+}
+
+func TestTumblingWindowsAllowedLateness(t *testing.T) {
+	var dropped []reading
+	items := streamReadings(
+		reading{10 * time.Second, 1},
+		reading{65 * time.Second, 2},
+		// Late, but within the allowed lateness.
+		reading{30 * time.Second, 5},
+		reading{150 * time.Second, 1},
+		// Too late.
+		reading{40 * time.Second, 7},
+	)
+
+	results := concurrent.TumblingWindows(context.Background(), items, time.Minute, readingTime, sumReadings,
+		concurrent.WithAllowedLateness(time.Minute),
+		concurrent.WithDroppedItems(func(r reading) { dropped = append(dropped, r) }))
+
+	assertWindows(t, results, []result{
+		{0, time.Minute, 1, false},
+		{0, time.Minute, 6, true},
+		{time.Minute, 2 * time.Minute, 2, false},
+		{2 * time.Minute, 3 * time.Minute, 1, false},
+	})
+	if !slices.Equal(dropped, []reading{{40 * time.Second, 7}}) {
+		t.Errorf("Expected the too late reading to be dropped, got %v", dropped)
+	}
+}
+
+func TestTumblingWindowsNilDroppedItems(t *testing.T) {
+	items := streamReadings(
+		reading{65 * time.Second, 2},
+		// Too late.
+		reading{10 * time.Second, 1},
+	)
+
+	results := concurrent.TumblingWindows(context.Background(), items, time.Minute, readingTime, sumReadings,
+		concurrent.WithDroppedItems[int](nil))
+
+	assertWindows(t, results, []result{
+		{time.Minute, 2 * time.Minute, 2, false},
+	})
+}
+
+func TestSlidingWindows(t *testing.T) {
+	items := streamReadings(
+		reading{30 * time.Second, 1},
+		reading{90 * time.Second, 2},
+	)
+
+	results := concurrent.SlidingWindows(context.Background(), items, 2*time.Minute, time.Minute, readingTime, sumReadings)
+
+	assertWindows(t, results, []result{
+		{-time.Minute, time.Minute, 1, false},
+		{0, 2 * time.Minute, 3, false},
+		{time.Minute, 3 * time.Minute, 2, false},
+	})
+}
+
+func TestSessionWindows(t *testing.T) {
+	items := streamReadings(
+		reading{0, 1},
+		reading{30 * time.Second, 1},
+		reading{120 * time.Second, 1},
+		// Late, and bridges the two sessions.
+		reading{75 * time.Second, 1},
+	)
+
+	results := concurrent.SessionWindows(context.Background(), items, time.Minute, readingTime, sumReadings,
+		concurrent.WithAllowedLateness(time.Minute))
+
+	assertWindows(t, results, []result{
+		{0, 90 * time.Second, 2, false},
+		{0, 3 * time.Minute, 4, true},
+	})
+}
+
+func TestWindowsIdleTimeout(t *testing.T) {
+	clock := newFakeClock()
+	items := make(chan reading)
+	results := concurrent.TumblingWindows(context.Background(), items, time.Minute, readingTime, sumReadings,
+		concurrent.WithIdleTimeout(10*time.Second), concurrent.WithClock(clock))
+
+	items <- reading{10 * time.Second, 1}
+	select {
+	case r := <-results:
+		t.Fatalf("Unexpected result before the watermark passed the window: %v", r)
+	default:
+	}
+	// No item arrives for 55s, so the watermark reaches 65s.
+	clock.Advance(55 * time.Second)
+
+	select {
+	case r := <-results:
+		if r.Window.Start != epoch || r.Value != 1 {
+			t.Errorf("Expected the first window with 1, got %v", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the idle timeout to advance the watermark")
+	}
+	close(items)
+	for range results {
+	}
+}
+
+func TestWindowsContextCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	items := make(chan reading)
+
+	results := concurrent.TumblingWindows(ctx, items, time.Minute, readingTime, sumReadings)
+	items <- reading{10 * time.Second, 1}
+	cancel()
+
+	for r := range results {
+		t.Errorf("Unexpected result after cancellation: %v", r)
+	}
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestWindowsInvalidSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic")
+		}
+	}()
+	concurrent.TumblingWindows(context.Background(), make(chan reading), 0, readingTime, sumReadings)
+}
+
+// fakeClock is a Clock whose time only moves when advanced.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{deadline: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the time forward, firing the waiters whose deadline passed.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.waiters = slices.DeleteFunc(c.waiters, func(w fakeWaiter) bool {
+		if w.deadline.After(c.now) {
+			return false
+		}
+		w.ch <- c.now
+		return true
+	})
+}
@@ -1,3 +1,3 @@
 module github.com/f0rmiga/datanalgo
 
-go 1.20
+go 1.24
@@ -0,0 +1,109 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lists
+
+import "iter"
+
+// FromSlice returns a new singly linked list holding the values of the given slice, in order.
+func FromSlice[T comparable](values []T) *SinglyLinkedList[T] {
+	list := &SinglyLinkedList[T]{}
+	for _, value := range values {
+		list.InsertLast(value)
+	}
+	return list
+}
+
+// Equal reports whether two linked lists hold the same values in the same order. The lists may be
+// of different implementations.
+func Equal[T comparable](a, b LinkedList[T]) bool {
+	return EqualFunc(a, b, func(x, y T) bool { return x == y })
+}
+
+// EqualFunc reports whether two linked lists hold the same values in the same order, using the eq
+// function to compare values. The lists may be of different implementations.
+func EqualFunc[T any](a, b LinkedList[T], eq func(x, y T) bool) bool {
+	if a.Size() != b.Size() {
+		return false
+	}
+	next, stop := iter.Pull(b.All())
+	defer stop()
+	for value := range a.All() {
+		other, ok := next()
+		if !ok || !eq(value, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// ToSlice returns a new slice holding the values of the list, from the head to the tail.
+func (list *singlyLinked[T]) ToSlice() []T {
+	values := make([]T, 0, list.size)
+	for current := list.head; current != nil; current = current.next {
+		values = append(values, current.value)
+	}
+	return values
+}
+
+// Clone returns a copy of the list. The copy has its own nodes, so changes to either list do not
+// affect the other; the values themselves are copied by assignment.
+func (list *SinglyLinkedList[T]) Clone() *SinglyLinkedList[T] {
+	return &SinglyLinkedList[T]{singlyLinked: list.clone()}
+}
+
+// Clone returns a copy of the list with the same eq function. The copy has its own nodes, so
+// changes to either list do not affect the other; the values themselves are copied by assignment.
+func (list *SinglyLinkedListFunc[T]) Clone() *SinglyLinkedListFunc[T] {
+	return &SinglyLinkedListFunc[T]{singlyLinked: list.clone(), eq: list.eq}
+}
+
+// clone returns a copy of the nodes of the list.
+func (list *singlyLinked[T]) clone() singlyLinked[T] {
+	var clone singlyLinked[T]
+	for current := list.head; current != nil; current = current.next {
+		clone.InsertLast(current.value)
+	}
+	return clone
+}
+
+// ToSlice returns a new slice holding the values of the list, from the head to the tail.
+func (list *doublyLinked[T]) ToSlice() []T {
+	values := make([]T, 0, list.size)
+	for current := list.head; current != nil; current = current.next {
+		values = append(values, current.value)
+	}
+	return values
+}
+
+// Clone returns a copy of the list. The copy has its own nodes, so changes to either list do not
+// affect the other; the values themselves are copied by assignment.
+func (list *DoublyLinkedList[T]) Clone() *DoublyLinkedList[T] {
+	return &DoublyLinkedList[T]{doublyLinked: list.clone()}
+}
+
+// Clone returns a copy of the list with the same eq function. The copy has its own nodes, so
+// changes to either list do not affect the other; the values themselves are copied by assignment.
+func (list *DoublyLinkedListFunc[T]) Clone() *DoublyLinkedListFunc[T] {
+	return &DoublyLinkedListFunc[T]{doublyLinked: list.clone(), eq: list.eq}
+}
+
+// clone returns a copy of the nodes of the list.
+func (list *doublyLinked[T]) clone() doublyLinked[T] {
+	var clone doublyLinked[T]
+	for current := list.head; current != nil; current = current.next {
+		clone.InsertLast(current.value)
+	}
+	return clone
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lists_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/f0rmiga/datanalgo/lists"
+)
+
+func TestFromSlice(t *testing.T) {
+	list := lists.FromSlice([]int{1, 2, 3})
+
+	assertList(t, list, "1 -> 2 -> 3 -> nil", 3)
+
+	list.InsertLast(4)
+	assertList(t, list, "1 -> 2 -> 3 -> 4 -> nil", 4)
+}
+
+func TestFromSliceEmpty(t *testing.T) {
+	list := lists.FromSlice[int](nil)
+
+	assertList(t, list, "nil", 0)
+}
+
+func TestToSlice(t *testing.T) {
+	list := lists.FromSlice([]int{1, 2, 3})
+
+	if actual := list.ToSlice(); !slices.Equal(actual, []int{1, 2, 3}) {
+		t.Errorf("Unexpected slice: %v", actual)
+	}
+
+	if actual := (&lists.SinglyLinkedList[int]{}).ToSlice(); actual == nil || len(actual) != 0 {
+		t.Errorf("Expected empty non-nil slice, got: %#v", actual)
+	}
+}
+
+func TestClone(t *testing.T) {
+	list := lists.FromSlice([]int{1, 2, 3})
+	clone := list.Clone()
+
+	clone.InsertLast(4)
+	clone.DeleteFirst()
+	list.Reverse()
+
+	assertList(t, list, "3 -> 2 -> 1 -> nil", 3)
+	assertList(t, clone, "2 -> 3 -> 4 -> nil", 3)
+}
+
+func TestDoublyLinkedListToSliceAndClone(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	clone := list.Clone()
+	clone.InsertFirst(0)
+	list.DeleteLast()
+
+	if actual := list.ToSlice(); !slices.Equal(actual, []int{1}) {
+		t.Errorf("Unexpected slice: %v", actual)
+	}
+	if actual := clone.ToSlice(); !slices.Equal(actual, []int{0, 1, 2}) {
+		t.Errorf("Unexpected clone slice: %v", actual)
+	}
+	if actual := slices.Collect(clone.Backward()); !slices.Equal(actual, []int{2, 1, 0}) {
+		t.Errorf("Unexpected clone backward values: %v", actual)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	singly := lists.FromSlice([]int{1, 2, 3})
+	doubly := &lists.DoublyLinkedList[int]{}
+	doubly.InsertLast(1)
+	doubly.InsertLast(2)
+	doubly.InsertLast(3)
+
+	if !lists.Equal[int](singly, doubly) {
+		t.Error("Expected lists with the same values to be equal")
+	}
+	if !lists.Equal[int](singly, singly.Clone()) {
+		t.Error("Expected a list to equal its clone")
+	}
+
+	doubly.DeleteLast()
+	if lists.Equal[int](singly, doubly) {
+		t.Error("Expected lists of different sizes not to be equal")
+	}
+
+	doubly.InsertLast(4)
+	if lists.Equal[int](singly, doubly) {
+		t.Error("Expected lists with different values not to be equal")
+	}
+
+	if !lists.Equal[int](&lists.SinglyLinkedList[int]{}, &lists.DoublyLinkedList[int]{}) {
+		t.Error("Expected empty lists to be equal")
+	}
+}
+
+func TestEqualFunc(t *testing.T) {
+	a := lists.NewSinglyLinkedListFunc(slices.Equal[[]int])
+	a.InsertLast([]int{1, 2})
+	a.InsertLast([]int{3})
+	b := lists.NewDoublyLinkedListFunc(slices.Equal[[]int])
+	b.InsertLast([]int{1, 2})
+	b.InsertLast([]int{3})
+
+	if !lists.EqualFunc[[]int](a, b, slices.Equal[[]int]) {
+		t.Error("Expected lists with the same values to be equal")
+	}
+
+	b.InsertLast(nil)
+	a.InsertLast([]int{4})
+	if lists.EqualFunc[[]int](a, b, slices.Equal[[]int]) {
+		t.Error("Expected lists with different values not to be equal")
+	}
+}
+
+func TestCloneKeepsEqualityFunc(t *testing.T) {
+	list := lists.NewSinglyLinkedListFunc(slices.Equal[[]int])
+	list.InsertLast([]int{1})
+	clone := list.Clone()
+
+	if index, err := clone.Search([]int{1}); err != nil || index != 0 {
+		t.Errorf("Unexpected search result: %d, %v", index, err)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lists
+
+// Cursor is a stateful position in a SinglyLinkedList or SinglyLinkedListFunc that allows reading and editing the list
+// around the current node in constant time while walking it. A new cursor is positioned before the
+// first element; call Next to advance it onto an element.
+//
+// A cursor remembers the node it is on and the node before it. It is invalidated by any change to
+// the list made through anything other than the cursor itself that removes either of those nodes
+// or inserts a node between them, such as DeleteAt, DeleteValue or InsertAt; using an invalidated
+// cursor leaves the list in an undefined state. Changes made through the cursor keep the list's
+// head, tail and size consistent, but invalidate every other cursor on the same list.
+type Cursor[T any] struct {
+	list *singlyLinked[T]
+	prev *Node[T]
+	cur  *Node[T]
+}
+
+// Cursor returns a new cursor positioned before the first element of the list.
+func (list *singlyLinked[T]) Cursor() *Cursor[T] {
+	return &Cursor[T]{list: list}
+}
+
+// Next advances the cursor to the next element. Returns false if there are no more elements, in
+// which case the cursor is no longer positioned on an element. After Remove, Next moves onto the
+// element that followed the removed one.
+func (c *Cursor[T]) Next() bool {
+	var next *Node[T]
+	switch {
+	case c.cur != nil:
+		c.prev = c.cur
+		next = c.cur.next
+	case c.prev != nil:
+		next = c.prev.next
+	default:
+		next = c.list.head
+	}
+	c.cur = next
+	return c.cur != nil
+}
+
+// Value returns the value of the current element. It must only be called after Next returned true
+// and before Remove; otherwise it returns the zero value of T.
+func (c *Cursor[T]) Value() (val T) {
+	if c.cur == nil {
+		return val
+	}
+	return c.cur.value
+}
+
+// Set replaces the value of the current element. Returns ErrNoCurrent if the cursor is not
+// positioned on an element.
+func (c *Cursor[T]) Set(value T) error {
+	if c.cur == nil {
+		return ErrNoCurrent
+	}
+	c.cur.value = value
+	return nil
+}
+
+// InsertAfter inserts a new node with the given value right after the current element. The cursor
+// stays on the current element, so the next call to Next moves onto the inserted value. Returns
+// ErrNoCurrent if the cursor is not positioned on an element.
+func (c *Cursor[T]) InsertAfter(value T) error {
+	if c.cur == nil {
+		return ErrNoCurrent
+	}
+	newNode := &Node[T]{value: value, next: c.cur.next}
+	c.cur.next = newNode
+	if c.list.tail == c.cur {
+		c.list.tail = newNode
+	}
+	c.list.size++
+	return nil
+}
+
+// InsertBefore inserts a new node with the given value right before the current element. The
+// cursor stays on the current element, so the inserted value is not visited by Next. Returns
+// ErrNoCurrent if the cursor is not positioned on an element.
+func (c *Cursor[T]) InsertBefore(value T) error {
+	if c.cur == nil {
+		return ErrNoCurrent
+	}
+	newNode := &Node[T]{value: value, next: c.cur}
+	if c.prev == nil {
+		c.list.head = newNode
+	} else {
+		c.prev.next = newNode
+	}
+	c.prev = newNode
+	c.list.size++
+	return nil
+}
+
+// Remove deletes the current element from the list and returns its value. Afterwards the cursor
+// is not positioned on an element until the next call to Next, which moves onto the element that
+// followed the removed one. Returns ErrNoCurrent if the cursor is not positioned on an element.
+func (c *Cursor[T]) Remove() (val T, err error) {
+	if c.cur == nil {
+		return val, ErrNoCurrent
+	}
+	removed := c.cur
+	if c.prev == nil {
+		c.list.head = removed.next
+	} else {
+		c.prev.next = removed.next
+	}
+	if c.list.tail == removed {
+		c.list.tail = c.prev
+	}
+	c.list.size--
+	c.cur = nil
+	return removed.value, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lists_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/f0rmiga/datanalgo/lists"
+)
+
+func TestCursorWalk(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.InsertLast(3)
+
+	var visited []int
+	cursor := list.Cursor()
+	for cursor.Next() {
+		visited = append(visited, cursor.Value())
+	}
+
+	if len(visited) != 3 || visited[0] != 1 || visited[1] != 2 || visited[2] != 3 {
+		t.Errorf("Unexpected visited values: %v", visited)
+	}
+
+	if cursor.Next() {
+		t.Error("Expected exhausted cursor to stay exhausted")
+	}
+}
+
+func TestCursorRemoveFilter(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	for i := 1; i <= 6; i++ {
+		list.InsertLast(i)
+	}
+
+	cursor := list.Cursor()
+	for cursor.Next() {
+		if cursor.Value()%2 == 0 {
+			if _, err := cursor.Remove(); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}
+	}
+
+	if list.String() != "1 -> 3 -> 5 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+
+	if list.Size() != 3 {
+		t.Errorf("Unexpected size: %d", list.Size())
+	}
+
+	list.InsertLast(7)
+	if list.String() != "1 -> 3 -> 5 -> 7 -> nil" {
+		t.Errorf("Unexpected list state after InsertLast: %s", list.String())
+	}
+}
+
+func TestCursorRemoveAll(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+
+	cursor := list.Cursor()
+	for cursor.Next() {
+		cursor.Remove()
+	}
+
+	if !list.IsEmpty() {
+		t.Errorf("Expected empty list, got: %s", list.String())
+	}
+
+	list.InsertLast(3)
+	if list.String() != "3 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestCursorSet(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+
+	cursor := list.Cursor()
+	for cursor.Next() {
+		cursor.Set(cursor.Value() * 10)
+	}
+
+	if list.String() != "10 -> 20 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestCursorInsertAfter(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(3)
+
+	var visited []int
+	cursor := list.Cursor()
+	for cursor.Next() {
+		visited = append(visited, cursor.Value())
+		if cursor.Value() == 1 {
+			cursor.InsertAfter(2)
+		}
+		if cursor.Value() == 3 {
+			cursor.InsertAfter(4)
+		}
+	}
+
+	if len(visited) != 4 {
+		t.Errorf("Expected inserted values to be visited, got: %v", visited)
+	}
+
+	list.InsertLast(5)
+	if list.String() != "1 -> 2 -> 3 -> 4 -> 5 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+
+	if list.Size() != 5 {
+		t.Errorf("Unexpected size: %d", list.Size())
+	}
+}
+
+func TestCursorInsertBefore(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(2)
+	list.InsertLast(4)
+
+	visited := 0
+	cursor := list.Cursor()
+	for cursor.Next() {
+		visited++
+		cursor.InsertBefore(cursor.Value() - 1)
+	}
+
+	if visited != 2 {
+		t.Errorf("Expected inserted values not to be visited, got %d visits", visited)
+	}
+
+	if list.String() != "1 -> 2 -> 3 -> 4 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+
+	if list.Size() != 4 {
+		t.Errorf("Unexpected size: %d", list.Size())
+	}
+}
+
+func TestCursorInsertBeforeAfterRemove(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.InsertLast(3)
+
+	cursor := list.Cursor()
+	cursor.Next()
+	cursor.Remove()
+	cursor.Next()
+	cursor.InsertBefore(0)
+
+	if list.String() != "0 -> 2 -> 3 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestCursorNoCurrent(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+
+	cursor := list.Cursor()
+	if err := cursor.Set(2); !errors.Is(err, lists.ErrNoCurrent) {
+		t.Errorf("Expected ErrNoCurrent before Next, got: %v", err)
+	}
+
+	cursor.Next()
+	cursor.Remove()
+	if _, err := cursor.Remove(); !errors.Is(err, lists.ErrNoCurrent) {
+		t.Errorf("Expected ErrNoCurrent after Remove, got: %v", err)
+	}
+
+	cursor.Next()
+	if err := cursor.InsertAfter(2); !errors.Is(err, lists.ErrNoCurrent) {
+		t.Errorf("Expected ErrNoCurrent after exhaustion, got: %v", err)
+	}
+	if err := cursor.InsertBefore(2); !errors.Is(err, lists.ErrNoCurrent) {
+		t.Errorf("Expected ErrNoCurrent after exhaustion, got: %v", err)
+	}
+}
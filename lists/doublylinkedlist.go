@@ -0,0 +1,356 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lists
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// DoublyNode represents a node in the doubly linked list. It contains a value of type T and
+// pointers to the previous and next nodes.
+type DoublyNode[T any] struct {
+	value T
+	prev  *DoublyNode[T]
+	next  *DoublyNode[T]
+}
+
+// DoublyLinkedList represents a doubly linked list with head and tail pointers and size. The zero
+// value is an empty list ready to use; lists of non-comparable values are provided by
+// DoublyLinkedListFunc.
+type DoublyLinkedList[T comparable] struct {
+	doublyLinked[T]
+}
+
+// DoublyLinkedListFunc is a doubly linked list that compares values with a function instead of ==
+// in Search, DeleteValue and DeleteAll, so that it can store values of any type, including
+// non-comparable ones such as slices or maps. It must be created with NewDoublyLinkedListFunc.
+type DoublyLinkedListFunc[T any] struct {
+	doublyLinked[T]
+	eq func(a, b T) bool
+}
+
+// doublyLinked holds the nodes of a doubly linked list and implements the operations that do not
+// compare values, which DoublyLinkedList and DoublyLinkedListFunc share.
+type doublyLinked[T any] struct {
+	head *DoublyNode[T]
+	tail *DoublyNode[T]
+	size int
+}
+
+var (
+	_ LinkedList[struct{}] = (*DoublyLinkedList[struct{}])(nil)
+	_ LinkedList[[]int]    = (*DoublyLinkedListFunc[[]int])(nil)
+)
+
+// NewDoublyLinkedListFunc returns a new empty list that uses the eq function to compare values.
+func NewDoublyLinkedListFunc[T any](eq func(a, b T) bool) *DoublyLinkedListFunc[T] {
+	return &DoublyLinkedListFunc[T]{eq: eq}
+}
+
+// InsertFirst inserts a new node with the given value at the beginning of the list.
+func (list *doublyLinked[T]) InsertFirst(value T) {
+	newNode := &DoublyNode[T]{value: value, next: list.head}
+	if list.head == nil {
+		list.tail = newNode
+	} else {
+		list.head.prev = newNode
+	}
+	list.head = newNode
+	list.size++
+}
+
+// InsertLast inserts a new node with the given value at the end of the list.
+func (list *doublyLinked[T]) InsertLast(value T) {
+	newNode := &DoublyNode[T]{value: value, prev: list.tail}
+	if list.tail == nil {
+		list.head = newNode
+	} else {
+		list.tail.next = newNode
+	}
+	list.tail = newNode
+	list.size++
+}
+
+// InsertAt inserts a new node with the given value at the specified index in the list. Returns an
+// *IndexError if the index is out of range.
+func (list *doublyLinked[T]) InsertAt(value T, index int) error {
+	if index < 0 || index > list.size {
+		return &IndexError{Index: index, Size: list.size}
+	}
+	if index == 0 {
+		list.InsertFirst(value)
+		return nil
+	}
+	if index == list.size {
+		list.InsertLast(value)
+		return nil
+	}
+	current := list.nodeAt(index)
+	newNode := &DoublyNode[T]{value: value, prev: current.prev, next: current}
+	current.prev.next = newNode
+	current.prev = newNode
+	list.size++
+	return nil
+}
+
+// DeleteFirst deletes the first node in the list and returns its value. Returns ErrEmptyList if the
+// list is empty.
+func (list *doublyLinked[T]) DeleteFirst() (val T, err error) {
+	if list.head == nil {
+		return val, ErrEmptyList
+	}
+	node := list.head
+	list.unlink(node)
+	return node.value, nil
+}
+
+// DeleteLast deletes the last node in the list and returns its value. Returns ErrEmptyList if the
+// list is empty.
+func (list *doublyLinked[T]) DeleteLast() (val T, err error) {
+	if list.tail == nil {
+		return val, ErrEmptyList
+	}
+	node := list.tail
+	list.unlink(node)
+	return node.value, nil
+}
+
+// DeleteAt deletes the node at the specified index in the list and returns its value. Returns an
+// *IndexError if the index is out of range.
+func (list *doublyLinked[T]) DeleteAt(index int) (val T, err error) {
+	if index < 0 || index >= list.size {
+		return val, &IndexError{Index: index, Size: list.size}
+	}
+	node := list.nodeAt(index)
+	list.unlink(node)
+	return node.value, nil
+}
+
+// DeleteValue deletes the first occurrence of the given value in the list. Returns true if the
+// value was found and deleted, false if the value was not found. Returns ErrEmptyList if the list
+// is empty.
+func (list *DoublyLinkedList[T]) DeleteValue(value T) (bool, error) {
+	if list.head == nil {
+		return false, ErrEmptyList
+	}
+	for current := list.head; current != nil; current = current.next {
+		if current.value == value {
+			list.unlink(current)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// DeleteValue deletes the first value in the list that is equal to the given one according to the
+// list's eq function. Returns true if the value was found and deleted, false if the value was not
+// found. Returns ErrEmptyList if the list is empty.
+func (list *DoublyLinkedListFunc[T]) DeleteValue(value T) (bool, error) {
+	if list.head == nil {
+		return false, ErrEmptyList
+	}
+	for current := list.head; current != nil; current = current.next {
+		if list.eq(current.value, value) {
+			list.unlink(current)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Search searches for the given value in the list and returns the index of the first occurrence.
+// Returns -1 if the value is not found. Returns ErrEmptyList if the list is empty.
+func (list *DoublyLinkedList[T]) Search(value T) (int, error) {
+	if list.head == nil {
+		return -1, ErrEmptyList
+	}
+	index := 0
+	for current := list.head; current != nil; current = current.next {
+		if current.value == value {
+			return index, nil
+		}
+		index++
+	}
+	return -1, nil
+}
+
+// Search returns the index of the first value in the list that is equal to the given one according
+// to the list's eq function. Returns -1 if the value is not found. Returns ErrEmptyList if the
+// list is empty.
+func (list *DoublyLinkedListFunc[T]) Search(value T) (int, error) {
+	if list.head == nil {
+		return -1, ErrEmptyList
+	}
+	return list.IndexFunc(func(v T) bool { return list.eq(v, value) }), nil
+}
+
+// DeleteFunc deletes every node whose value satisfies pred in a single pass and returns the number
+// of nodes deleted.
+func (list *doublyLinked[T]) DeleteFunc(pred func(T) bool) int {
+	deleted := 0
+	for current := list.head; current != nil; {
+		next := current.next
+		if pred(current.value) {
+			list.unlink(current)
+			deleted++
+		}
+		current = next
+	}
+	return deleted
+}
+
+// DeleteAll deletes every occurrence of the given value in the list and returns the number of
+// nodes deleted.
+func (list *DoublyLinkedList[T]) DeleteAll(value T) int {
+	return list.DeleteFunc(func(v T) bool { return v == value })
+}
+
+// DeleteAll deletes every value in the list that is equal to the given one according to the
+// list's eq function and returns the number of nodes deleted.
+func (list *DoublyLinkedListFunc[T]) DeleteAll(value T) int {
+	return list.DeleteFunc(func(v T) bool { return list.eq(v, value) })
+}
+
+// IndexFunc returns the index of the first node whose value satisfies pred, or -1 if there is
+// none.
+func (list *doublyLinked[T]) IndexFunc(pred func(T) bool) int {
+	index := 0
+	for current := list.head; current != nil; current = current.next {
+		if pred(current.value) {
+			return index
+		}
+		index++
+	}
+	return -1
+}
+
+// ContainsFunc reports whether at least one node's value satisfies pred.
+func (list *doublyLinked[T]) ContainsFunc(pred func(T) bool) bool {
+	return list.IndexFunc(pred) >= 0
+}
+
+// Traversal traverses the list from the head to the tail, calling the given function for each
+// node's value. Returns an error if the function returns an error for any value.
+func (list *doublyLinked[T]) Traversal(fn func(T) error) error {
+	for current := list.head; current != nil; current = current.next {
+		if err := fn(current.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReverseTraversal traverses the list from the tail to the head, calling the given function for
+// each node's value. Returns an error if the function returns an error for any value.
+func (list *doublyLinked[T]) ReverseTraversal(fn func(T) error) error {
+	for current := list.tail; current != nil; current = current.prev {
+		if err := fn(current.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// All returns an iterator over the values of the list, from the head to the tail.
+func (list *doublyLinked[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for current := list.head; current != nil; current = current.next {
+			if !yield(current.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the values of the list, from the tail to the head.
+func (list *doublyLinked[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for current := list.tail; current != nil; current = current.prev {
+			if !yield(current.value) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over the index and value of each node, from the head to the tail.
+func (list *doublyLinked[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		index := 0
+		for current := list.head; current != nil; current = current.next {
+			if !yield(index, current.value) {
+				return
+			}
+			index++
+		}
+	}
+}
+
+// Size returns the size of the list (number of nodes).
+func (list *doublyLinked[T]) Size() int {
+	return list.size
+}
+
+// IsEmpty returns true if the list is empty, false otherwise.
+func (list *doublyLinked[T]) IsEmpty() bool {
+	return list.size == 0
+}
+
+// String returns a string representation of the list, with each value followed by an arrow ("->")
+// pointing to the next value. The last value points to "nil", indicating the end of the list.
+func (list *doublyLinked[T]) String() string {
+	var sb strings.Builder
+	for current := list.head; current != nil; current = current.next {
+		fmt.Fprintf(&sb, "%v -> ", current.value)
+	}
+	sb.WriteString("nil")
+	return sb.String()
+}
+
+// nodeAt returns the node at the given index, walking from whichever end of the list is closer.
+// The index must be within range.
+func (list *doublyLinked[T]) nodeAt(index int) *DoublyNode[T] {
+	if index < list.size/2 {
+		current := list.head
+		for i := 0; i < index; i++ {
+			current = current.next
+		}
+		return current
+	}
+	current := list.tail
+	for i := list.size - 1; i > index; i-- {
+		current = current.prev
+	}
+	return current
+}
+
+// unlink removes the given node from the list, fixing up the head and tail pointers as needed.
+func (list *doublyLinked[T]) unlink(node *DoublyNode[T]) {
+	if node.prev == nil {
+		list.head = node.next
+	} else {
+		node.prev.next = node.next
+	}
+	if node.next == nil {
+		list.tail = node.prev
+	} else {
+		node.next.prev = node.prev
+	}
+	node.prev = nil
+	node.next = nil
+	list.size--
+}
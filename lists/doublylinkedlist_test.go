@@ -0,0 +1,817 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lists_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/f0rmiga/datanalgo/lists"
+)
+
+func TestDoublyLinkedListInsertFirst(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(2)
+	list.InsertFirst(3)
+
+	if list.String() != "3 -> 2 -> 1 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListInsertLast(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.InsertLast(3)
+
+	if list.String() != "1 -> 2 -> 3 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListInsertAt(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(3)
+	list.InsertAt(2, 1)
+
+	if list.String() != "3 -> 2 -> 1 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListInsertAtMiddle(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(2)
+	list.InsertFirst(3)
+	err := list.InsertAt(4, 2)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if list.String() != "3 -> 2 -> 4 -> 1 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListInsertAtEnd(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(2)
+	list.InsertFirst(3)
+	err := list.InsertAt(4, 3)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if list.String() != "3 -> 2 -> 1 -> 4 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListInsertAtZeroIndex(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(2)
+	list.InsertFirst(3)
+
+	err := list.InsertAt(4, 0)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if list.String() != "4 -> 3 -> 2 -> 1 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListInsertAtOutOfRange(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(2)
+	list.InsertFirst(3)
+
+	err := list.InsertAt(4, 5)
+
+	if err == nil {
+		t.Error("Expected error for index out of range")
+	}
+}
+
+func TestDoublyLinkedListIndexError(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+
+	err := list.InsertAt(3, 5)
+	if !errors.Is(err, lists.ErrIndexOutOfRange) {
+		t.Errorf("Expected ErrIndexOutOfRange, got: %v", err)
+	}
+
+	_, err = list.DeleteAt(-1)
+	var indexErr *lists.IndexError
+	if !errors.As(err, &indexErr) {
+		t.Fatalf("Expected *IndexError, got: %v", err)
+	}
+	if indexErr.Index != -1 || indexErr.Size != 2 {
+		t.Errorf("Unexpected index error: %+v", indexErr)
+	}
+}
+
+func TestDoublyLinkedListEmptyListErrors(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+
+	if _, err := list.DeleteFirst(); !errors.Is(err, lists.ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList from DeleteFirst, got: %v", err)
+	}
+	if _, err := list.DeleteLast(); !errors.Is(err, lists.ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList from DeleteLast, got: %v", err)
+	}
+	if _, err := list.DeleteValue(1); !errors.Is(err, lists.ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList from DeleteValue, got: %v", err)
+	}
+	if _, err := list.Search(1); !errors.Is(err, lists.ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList from Search, got: %v", err)
+	}
+}
+
+func TestDoublyLinkedListDeleteFirst(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(3)
+	list.InsertFirst(2)
+	list.InsertFirst(1)
+	list.DeleteFirst()
+
+	if list.String() != "2 -> 3 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListDeleteFirstEmptyList(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	_, err := list.DeleteFirst()
+
+	if err == nil {
+		t.Error("Expected error for empty list")
+	}
+}
+
+func TestDoublyLinkedListDeleteLast(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(3)
+	list.InsertFirst(2)
+	list.InsertFirst(1)
+	list.DeleteLast()
+
+	if list.String() != "1 -> 2 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListDeleteLastEmptyList(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	_, err := list.DeleteLast()
+
+	if err == nil {
+		t.Error("Expected error for empty list")
+	}
+}
+
+func TestDoublyLinkedListDeleteLastSingleElementList(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	value, err := list.DeleteLast()
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if value != 1 {
+		t.Errorf("Unexpected value: %d", value)
+	}
+
+	if !list.IsEmpty() {
+		t.Error("Expected empty list after deleting last element")
+	}
+}
+
+func TestDoublyLinkedListDeleteAt(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(3)
+	list.InsertFirst(2)
+	list.InsertFirst(1)
+	list.DeleteAt(1)
+
+	if list.String() != "1 -> 3 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListDeleteAtOutOfRange(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(2)
+	list.InsertFirst(3)
+
+	_, err := list.DeleteAt(5)
+
+	if err == nil {
+		t.Error("Expected error for index out of range")
+	}
+}
+
+func TestDoublyLinkedListDeleteAtZeroIndex(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(2)
+	list.InsertFirst(3)
+
+	value, err := list.DeleteAt(0)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if value != 3 {
+		t.Errorf("Unexpected value: %d", value)
+	}
+
+	if list.String() != "2 -> 1 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListDeleteAtMiddle(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(2)
+	list.InsertFirst(3)
+
+	value, err := list.DeleteAt(1)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if value != 2 {
+		t.Errorf("Unexpected value: %d", value)
+	}
+
+	if list.String() != "3 -> 1 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListDeleteAtEnd(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(2)
+	list.InsertFirst(3)
+
+	value, err := list.DeleteAt(2)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if value != 1 {
+		t.Errorf("Unexpected value: %d", value)
+	}
+
+	if list.String() != "3 -> 2 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListDeleteValue(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(3)
+	list.InsertFirst(2)
+	list.InsertFirst(1)
+	list.DeleteValue(2)
+
+	if list.String() != "1 -> 3 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListSearch(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(3)
+	list.InsertFirst(2)
+	list.InsertFirst(1)
+
+	index, _ := list.Search(2)
+	if index != 1 {
+		t.Errorf("Unexpected index: %d", index)
+	}
+}
+
+func TestDoublyLinkedListSearchEmptyList(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+
+	_, err := list.Search(1)
+
+	if err == nil {
+		t.Error("Expected error for empty list")
+	}
+}
+
+func TestDoublyLinkedListSearchNotFound(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(2)
+	list.InsertFirst(3)
+
+	index, err := list.Search(4)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if index != -1 {
+		t.Error("Expected index -1 for not found")
+	}
+}
+
+func TestDoublyLinkedListIndexFunc(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(4)
+	list.InsertLast(6)
+
+	isEven := func(value int) bool { return value%2 == 0 }
+	if index := list.IndexFunc(isEven); index != 1 {
+		t.Errorf("Unexpected index: %d", index)
+	}
+	if !list.ContainsFunc(isEven) {
+		t.Error("Expected an even value to be found")
+	}
+
+	isNegative := func(value int) bool { return value < 0 }
+	if index := list.IndexFunc(isNegative); index != -1 {
+		t.Errorf("Unexpected index: %d", index)
+	}
+	if list.ContainsFunc(isNegative) {
+		t.Error("Expected no negative value to be found")
+	}
+}
+
+func TestDoublyLinkedListDeleteFunc(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	for i := 1; i <= 6; i++ {
+		list.InsertLast(i)
+	}
+
+	deleted := list.DeleteFunc(func(value int) bool { return value%2 == 0 })
+
+	if deleted != 3 {
+		t.Errorf("Unexpected number of deleted values: %d", deleted)
+	}
+	if list.Size() != 3 {
+		t.Errorf("Unexpected size: %d", list.Size())
+	}
+
+	list.InsertLast(7)
+	if list.String() != "1 -> 3 -> 5 -> 7 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListDeleteFuncAll(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+
+	deleted := list.DeleteFunc(func(int) bool { return true })
+
+	if deleted != 2 || !list.IsEmpty() {
+		t.Errorf("Unexpected result: deleted %d, list %s", deleted, list.String())
+	}
+
+	list.InsertLast(3)
+	if list.String() != "3 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListDeleteAll(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	for _, value := range []int{2, 1, 2, 3, 2} {
+		list.InsertLast(value)
+	}
+
+	deleted := list.DeleteAll(2)
+
+	if deleted != 3 {
+		t.Errorf("Unexpected number of deleted values: %d", deleted)
+	}
+	if list.String() != "1 -> 3 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+	if deleted := list.DeleteAll(4); deleted != 0 {
+		t.Errorf("Unexpected number of deleted values: %d", deleted)
+	}
+}
+
+func TestDoublyLinkedListTraversal(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(3)
+	list.InsertFirst(2)
+	list.InsertFirst(1)
+
+	expected := "1 -> 2 -> 3 -> nil"
+	actual := ""
+	err := list.Traversal(func(value int) error {
+		actual += fmt.Sprintf("%d -> ", value)
+		return nil
+	})
+	actual += "nil"
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if actual != expected {
+		t.Errorf("Unexpected traversal: %s", actual)
+	}
+}
+
+func TestDoublyLinkedListTraversalWithError(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(2)
+	list.InsertFirst(3)
+
+	err := list.Traversal(func(value int) error {
+		if value == 2 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	if err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestDoublyLinkedListReverseTraversal(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(3)
+	list.InsertFirst(2)
+	list.InsertFirst(1)
+
+	expected := "3 -> 2 -> 1 -> nil"
+	actual := ""
+	err := list.ReverseTraversal(func(value int) error {
+		actual += fmt.Sprintf("%d -> ", value)
+		return nil
+	})
+	actual += "nil"
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if actual != expected {
+		t.Errorf("Unexpected reverse traversal: %s", actual)
+	}
+}
+
+func TestDoublyLinkedListReverseTraversalWithError(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(2)
+	list.InsertFirst(3)
+
+	visited := 0
+	err := list.ReverseTraversal(func(value int) error {
+		visited++
+		if value == 2 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	if err == nil {
+		t.Error("Expected error")
+	}
+
+	if visited != 2 {
+		t.Errorf("Unexpected number of visited values: %d", visited)
+	}
+}
+
+func TestDoublyLinkedListReverseTraversalAfterDeletes(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	for i := 1; i <= 5; i++ {
+		list.InsertLast(i)
+	}
+	list.DeleteFirst()
+	list.DeleteLast()
+	list.DeleteAt(1)
+	list.InsertAt(6, 1)
+
+	actual := ""
+	list.ReverseTraversal(func(value int) error {
+		actual += fmt.Sprintf("%d -> ", value)
+		return nil
+	})
+	actual += "nil"
+
+	if actual != "4 -> 6 -> 2 -> nil" {
+		t.Errorf("Unexpected reverse traversal: %s", actual)
+	}
+
+	if list.String() != "2 -> 6 -> 4 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListInsertLastAfterEmptied(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertLast(1)
+	list.DeleteLast()
+	list.InsertLast(2)
+	list.InsertFirst(1)
+
+	if list.String() != "1 -> 2 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+
+	if list.Size() != 2 {
+		t.Errorf("Unexpected size: %d", list.Size())
+	}
+}
+
+func TestDoublyLinkedListAll(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.InsertLast(3)
+
+	actual := slices.Collect(list.All())
+	if !slices.Equal(actual, []int{1, 2, 3}) {
+		t.Errorf("Unexpected values: %v", actual)
+	}
+}
+
+func TestDoublyLinkedListAllBreak(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.InsertLast(3)
+
+	var visited []int
+	for value := range list.All() {
+		visited = append(visited, value)
+		if value == 2 {
+			break
+		}
+	}
+
+	if !slices.Equal(visited, []int{1, 2}) {
+		t.Errorf("Unexpected visited values: %v", visited)
+	}
+}
+
+func TestDoublyLinkedListBackward(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.InsertLast(3)
+
+	actual := slices.Collect(list.Backward())
+	if !slices.Equal(actual, []int{3, 2, 1}) {
+		t.Errorf("Unexpected values: %v", actual)
+	}
+}
+
+func TestDoublyLinkedListBackwardBreak(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.InsertLast(3)
+
+	var visited []int
+	for value := range list.Backward() {
+		visited = append(visited, value)
+		break
+	}
+
+	if !slices.Equal(visited, []int{3}) {
+		t.Errorf("Unexpected visited values: %v", visited)
+	}
+}
+
+func TestDoublyLinkedListEnumerate(t *testing.T) {
+	list := &lists.DoublyLinkedList[string]{}
+	list.InsertLast("a")
+	list.InsertLast("b")
+	list.InsertLast("c")
+
+	var visited []string
+	for index, value := range list.Enumerate() {
+		visited = append(visited, fmt.Sprintf("%d:%s", index, value))
+		if index == 1 {
+			break
+		}
+	}
+
+	if !slices.Equal(visited, []string{"0:a", "1:b"}) {
+		t.Errorf("Unexpected visited values: %v", visited)
+	}
+}
+
+func TestDoublyLinkedListIteratorsEmptyList(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+
+	for range list.All() {
+		t.Error("Unexpected value from All")
+	}
+	for range list.Backward() {
+		t.Error("Unexpected value from Backward")
+	}
+	for range list.Enumerate() {
+		t.Error("Unexpected value from Enumerate")
+	}
+}
+
+func TestDoublyLinkedListSize(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(3)
+	list.InsertFirst(2)
+	list.InsertFirst(1)
+
+	if list.Size() != 3 {
+		t.Errorf("Unexpected size: %d", list.Size())
+	}
+}
+
+func TestDoublyLinkedListDeleteValueEmptyList(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+
+	found, err := list.DeleteValue(1)
+
+	if err == nil {
+		t.Error("Expected error for empty list")
+	}
+
+	if found {
+		t.Error("Expected value not found")
+	}
+}
+
+func TestDoublyLinkedListDeleteValueNotFound(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(2)
+	list.InsertFirst(3)
+
+	found, err := list.DeleteValue(4)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if found {
+		t.Error("Expected value not found")
+	}
+
+	if list.String() != "3 -> 2 -> 1 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListDeleteValueAtHead(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(1)
+	list.InsertFirst(2)
+	list.InsertFirst(3)
+
+	found, err := list.DeleteValue(3)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if !found {
+		t.Error("Expected value found")
+	}
+
+	if list.String() != "2 -> 1 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListIsEmpty(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+
+	if !list.IsEmpty() {
+		t.Errorf("Unexpected state: not empty")
+	}
+
+	list.InsertFirst(1)
+
+	if list.IsEmpty() {
+		t.Errorf("Unexpected state: empty")
+	}
+}
+
+func TestDoublyLinkedListString(t *testing.T) {
+	list := &lists.DoublyLinkedList[int]{}
+	list.InsertFirst(3)
+	list.InsertFirst(2)
+	list.InsertFirst(1)
+
+	expected := "1 -> 2 -> 3 -> nil"
+	actual := list.String()
+
+	if actual != expected {
+		t.Errorf("Unexpected string representation: %s", actual)
+	}
+}
+
+func TestDoublyLinkedListWithCustomStruct(t *testing.T) {
+	list := &lists.DoublyLinkedList[Person]{}
+
+	list.InsertFirst(Person{Name: "John", Age: 30})
+	list.InsertFirst(Person{Name: "Jane", Age: 25})
+	list.InsertLast(Person{Name: "Bob", Age: 40})
+
+	if list.Size() != 3 {
+		t.Errorf("Unexpected list size: %d", list.Size())
+	}
+
+	expected := "{Jane 25} -> {John 30} -> {Bob 40} -> nil"
+	if list.String() != expected {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+
+	index, err := list.Search(Person{Name: "John", Age: 30})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if index != 1 {
+		t.Errorf("Unexpected index: %d", index)
+	}
+
+	deleted, err := list.DeleteValue(Person{Name: "Jane", Age: 25})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !deleted {
+		t.Errorf("Expected Person value to be deleted")
+	}
+	expected = "{John 30} -> {Bob 40} -> nil"
+	if list.String() != expected {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDoublyLinkedListWithEqualityFunc(t *testing.T) {
+	list := lists.NewDoublyLinkedListFunc(slices.Equal[[]int])
+	list.InsertLast([]int{1})
+	list.InsertLast([]int{2, 3})
+	list.InsertLast([]int{4})
+
+	index, err := list.Search([]int{2, 3})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if index != 1 {
+		t.Errorf("Unexpected index: %d", index)
+	}
+
+	deleted, err := list.DeleteValue([]int{4})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !deleted {
+		t.Error("Expected value to be deleted")
+	}
+
+	if list.String() != "[1] -> [2 3] -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lists
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrIndexOutOfRange is returned when an index falls outside the bounds of a list. Errors
+	// carrying the offending index are returned as *IndexError, which matches ErrIndexOutOfRange
+	// with errors.Is.
+	ErrIndexOutOfRange = errors.New("index out of range")
+
+	// ErrEmptyList is returned by operations that require at least one element in the list.
+	ErrEmptyList = errors.New("list is empty")
+
+	// ErrNoCurrent is returned by cursor operations that require the cursor to be positioned on an
+	// element, such as before the first call to Next, after Next returned false or after Remove.
+	ErrNoCurrent = errors.New("cursor is not positioned on an element")
+)
+
+// IndexError describes an out of range index passed to a list operation. It holds the requested
+// index and the size of the list at the time of the call.
+type IndexError struct {
+	Index int
+	Size  int
+}
+
+// Error returns a string representation of the error.
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("index %d out of range for list of size %d", e.Index, e.Size)
+}
+
+// Unwrap returns ErrIndexOutOfRange so that errors.Is can be used to match any IndexError.
+func (e *IndexError) Unwrap() error {
+	return ErrIndexOutOfRange
+}
@@ -14,6 +14,8 @@
 
 package lists
 
+import "iter"
+
 // LinkedList represents a generic linked list that can store elements of any type.
 type LinkedList[T any] interface {
 	// InsertFirst adds an element to the beginning of the linked list.
@@ -40,6 +42,21 @@ type LinkedList[T any] interface {
 	// Search returns the index of the first occurrence of the specified value in the linked list.
 	Search(value T) (int, error)
 
+	// DeleteFunc removes every element of the linked list for which pred returns true, in a single
+	// pass, and returns the number of elements removed.
+	DeleteFunc(pred func(T) bool) int
+
+	// DeleteAll removes every occurrence of the specified value from the linked list and returns
+	// the number of elements removed.
+	DeleteAll(value T) int
+
+	// IndexFunc returns the index of the first element of the linked list for which pred returns
+	// true, or -1 if there is none.
+	IndexFunc(pred func(T) bool) int
+
+	// ContainsFunc reports whether pred returns true for at least one element of the linked list.
+	ContainsFunc(pred func(T) bool) bool
+
 	// Traversal applies the given function to each element of the linked list, in order.
 	Traversal(func(T) error) error
 
@@ -47,6 +64,16 @@ type LinkedList[T any] interface {
 	// order.
 	ReverseTraversal(func(T) error) error
 
+	// All returns an iterator over the elements of the linked list, in order.
+	All() iter.Seq[T]
+
+	// Backward returns an iterator over the elements of the linked list, in reverse order.
+	Backward() iter.Seq[T]
+
+	// Enumerate returns an iterator over the index and value of each element of the linked list,
+	// in order.
+	Enumerate() iter.Seq2[int, T]
+
 	// Size returns the number of elements in the linked list.
 	Size() int
 
@@ -15,58 +15,87 @@
 package lists
 
 import (
-	"errors"
 	"fmt"
+	"iter"
 	"strings"
 )
 
 // Node represents a node in the singly linked list. It contains a value of type T and a pointer to
 // the next node.
-type Node[T comparable] struct {
+type Node[T any] struct {
 	value T
 	next  *Node[T]
 }
 
-// SinglyLinkedList represents a singly linked list with a head pointer and size.
+// SinglyLinkedList represents a singly linked list with head and tail pointers and size. Tracking
+// the tail makes InsertLast a constant time operation. The zero value is an empty list ready to
+// use; lists of non-comparable values are provided by SinglyLinkedListFunc.
 type SinglyLinkedList[T comparable] struct {
+	singlyLinked[T]
+}
+
+// SinglyLinkedListFunc is a singly linked list that compares values with a function instead of ==
+// in Search, DeleteValue and DeleteAll, so that it can store values of any type, including
+// non-comparable ones such as slices or maps. It must be created with NewSinglyLinkedListFunc.
+type SinglyLinkedListFunc[T any] struct {
+	singlyLinked[T]
+	eq func(a, b T) bool
+}
+
+// singlyLinked holds the nodes of a singly linked list and implements the operations that do not
+// compare values, which SinglyLinkedList and SinglyLinkedListFunc share.
+type singlyLinked[T any] struct {
 	head *Node[T]
+	tail *Node[T]
 	size int
 }
 
-var _ LinkedList[struct{}] = (*SinglyLinkedList[struct{}])(nil)
+var (
+	_ LinkedList[struct{}] = (*SinglyLinkedList[struct{}])(nil)
+	_ LinkedList[[]int]    = (*SinglyLinkedListFunc[[]int])(nil)
+)
+
+// NewSinglyLinkedListFunc returns a new empty list that uses the eq function to compare values.
+func NewSinglyLinkedListFunc[T any](eq func(a, b T) bool) *SinglyLinkedListFunc[T] {
+	return &SinglyLinkedListFunc[T]{eq: eq}
+}
 
 // InsertFirst inserts a new node with the given value at the beginning of the list.
-func (list *SinglyLinkedList[T]) InsertFirst(value T) {
+func (list *singlyLinked[T]) InsertFirst(value T) {
 	newNode := &Node[T]{value: value, next: list.head}
+	if list.head == nil {
+		list.tail = newNode
+	}
 	list.head = newNode
 	list.size++
 }
 
 // InsertLast inserts a new node with the given value at the end of the list.
-func (list *SinglyLinkedList[T]) InsertLast(value T) {
+func (list *singlyLinked[T]) InsertLast(value T) {
 	newNode := &Node[T]{value: value, next: nil}
-	if list.head == nil {
+	if list.tail == nil {
 		list.head = newNode
 	} else {
-		current := list.head
-		for current.next != nil {
-			current = current.next
-		}
-		current.next = newNode
+		list.tail.next = newNode
 	}
+	list.tail = newNode
 	list.size++
 }
 
 // InsertAt inserts a new node with the given value at the specified index in the list. Returns an
-// error if the index is out of range.
-func (list *SinglyLinkedList[T]) InsertAt(value T, index int) error {
+// *IndexError if the index is out of range.
+func (list *singlyLinked[T]) InsertAt(value T, index int) error {
 	if index < 0 || index > list.size {
-		return errors.New("index out of range")
+		return &IndexError{Index: index, Size: list.size}
 	}
 	if index == 0 {
 		list.InsertFirst(value)
 		return nil
 	}
+	if index == list.size {
+		list.InsertLast(value)
+		return nil
+	}
 	newNode := &Node[T]{value: value, next: nil}
 	current := list.head
 	for i := 1; i < index; i++ {
@@ -78,26 +107,31 @@ func (list *SinglyLinkedList[T]) InsertAt(value T, index int) error {
 	return nil
 }
 
-// DeleteFirst deletes the first node in the list and returns its value. Returns an error if the
+// DeleteFirst deletes the first node in the list and returns its value. Returns ErrEmptyList if the
 // list is empty.
-func (list *SinglyLinkedList[T]) DeleteFirst() (val T, err error) {
+func (list *singlyLinked[T]) DeleteFirst() (val T, err error) {
 	if list.head == nil {
-		return val, errors.New("list is empty")
+		return val, ErrEmptyList
 	}
 	value := list.head.value
 	list.head = list.head.next
+	if list.head == nil {
+		list.tail = nil
+	}
 	list.size--
 	return value, nil
 }
 
-// DeleteLast deletes the last node in the list and returns its value.
-func (list *SinglyLinkedList[T]) DeleteLast() (val T, err error) {
+// DeleteLast deletes the last node in the list and returns its value. Returns ErrEmptyList if the
+// list is empty. Since nodes only link forward, finding the new tail requires walking the list.
+func (list *singlyLinked[T]) DeleteLast() (val T, err error) {
 	if list.head == nil {
-		return val, errors.New("list is empty")
+		return val, ErrEmptyList
 	}
 	if list.head.next == nil {
 		value := list.head.value
 		list.head = nil
+		list.tail = nil
 		list.size--
 		return value, nil
 	}
@@ -107,15 +141,16 @@ func (list *SinglyLinkedList[T]) DeleteLast() (val T, err error) {
 	}
 	value := current.next.value
 	current.next = nil
+	list.tail = current
 	list.size--
 	return value, nil
 }
 
 // DeleteAt deletes the node at the specified index in the list and returns its value. Returns an
-// error if the index is out of range.
-func (list *SinglyLinkedList[T]) DeleteAt(index int) (val T, err error) {
+// *IndexError if the index is out of range.
+func (list *singlyLinked[T]) DeleteAt(index int) (val T, err error) {
 	if index < 0 || index >= list.size {
-		return val, errors.New("index out of range")
+		return val, &IndexError{Index: index, Size: list.size}
 	}
 	if index == 0 {
 		return list.DeleteFirst()
@@ -126,39 +161,52 @@ func (list *SinglyLinkedList[T]) DeleteAt(index int) (val T, err error) {
 	}
 	value := current.next.value
 	current.next = current.next.next
+	if current.next == nil {
+		list.tail = current
+	}
 	list.size--
 	return value, nil
 }
 
 // DeleteValue deletes the first occurrence of the given value in the list. Returns true if the
-// value was found and deleted, false if the value was not found. Returns an error if the list is
-// empty.
+// value was found and deleted, false if the value was not found. Returns ErrEmptyList if the list
+// is empty.
 func (list *SinglyLinkedList[T]) DeleteValue(value T) (bool, error) {
 	if list.head == nil {
-		return false, errors.New("list is empty")
+		return false, ErrEmptyList
 	}
-	if list.head.value == value {
-		list.head = list.head.next
-		list.size--
-		return true, nil
+	var prev *Node[T]
+	for current := list.head; current != nil; prev, current = current, current.next {
+		if current.value == value {
+			list.unlinkAfter(prev)
+			return true, nil
+		}
 	}
-	current := list.head
-	for current.next != nil && current.next.value != value {
-		current = current.next
+	return false, nil
+}
+
+// DeleteValue deletes the first value in the list that is equal to the given one according to the
+// list's eq function. Returns true if the value was found and deleted, false if the value was not
+// found. Returns ErrEmptyList if the list is empty.
+func (list *SinglyLinkedListFunc[T]) DeleteValue(value T) (bool, error) {
+	if list.head == nil {
+		return false, ErrEmptyList
 	}
-	if current.next == nil {
-		return false, nil
+	var prev *Node[T]
+	for current := list.head; current != nil; prev, current = current, current.next {
+		if list.eq(current.value, value) {
+			list.unlinkAfter(prev)
+			return true, nil
+		}
 	}
-	current.next = current.next.next
-	list.size--
-	return true, nil
+	return false, nil
 }
 
 // Search searches for the given value in the list and returns the index of the first occurrence.
-// Returns -1 if the value is not found. Returns an error if the list is empty.
+// Returns -1 if the value is not found. Returns ErrEmptyList if the list is empty.
 func (list *SinglyLinkedList[T]) Search(value T) (int, error) {
 	if list.head == nil {
-		return -1, errors.New("list is empty")
+		return -1, ErrEmptyList
 	}
 	current := list.head
 	index := 0
@@ -172,9 +220,71 @@ func (list *SinglyLinkedList[T]) Search(value T) (int, error) {
 	return -1, nil
 }
 
+// Search returns the index of the first value in the list that is equal to the given one according
+// to the list's eq function. Returns -1 if the value is not found. Returns ErrEmptyList if the
+// list is empty.
+func (list *SinglyLinkedListFunc[T]) Search(value T) (int, error) {
+	if list.head == nil {
+		return -1, ErrEmptyList
+	}
+	return list.IndexFunc(func(v T) bool { return list.eq(v, value) }), nil
+}
+
+// DeleteFunc deletes every node whose value satisfies pred in a single pass and returns the number
+// of nodes deleted.
+func (list *singlyLinked[T]) DeleteFunc(pred func(T) bool) int {
+	deleted := 0
+	var prev *Node[T]
+	for current := list.head; current != nil; current = current.next {
+		if !pred(current.value) {
+			prev = current
+			continue
+		}
+		if prev == nil {
+			list.head = current.next
+		} else {
+			prev.next = current.next
+		}
+		deleted++
+	}
+	list.tail = prev
+	list.size -= deleted
+	return deleted
+}
+
+// DeleteAll deletes every occurrence of the given value in the list and returns the number of
+// nodes deleted.
+func (list *SinglyLinkedList[T]) DeleteAll(value T) int {
+	return list.DeleteFunc(func(v T) bool { return v == value })
+}
+
+// DeleteAll deletes every value in the list that is equal to the given one according to the
+// list's eq function and returns the number of nodes deleted.
+func (list *SinglyLinkedListFunc[T]) DeleteAll(value T) int {
+	return list.DeleteFunc(func(v T) bool { return list.eq(v, value) })
+}
+
+// IndexFunc returns the index of the first node whose value satisfies pred, or -1 if there is
+// none.
+func (list *singlyLinked[T]) IndexFunc(pred func(T) bool) int {
+	index := 0
+	for current := list.head; current != nil; current = current.next {
+		if pred(current.value) {
+			return index
+		}
+		index++
+	}
+	return -1
+}
+
+// ContainsFunc reports whether at least one node's value satisfies pred.
+func (list *singlyLinked[T]) ContainsFunc(pred func(T) bool) bool {
+	return list.IndexFunc(pred) >= 0
+}
+
 // Traversal traverses the list from the head to the tail, calling the given function for each
 // node's value. Returns an error if the function returns an error for any value.
-func (list *SinglyLinkedList[T]) Traversal(fn func(T) error) error {
+func (list *singlyLinked[T]) Traversal(fn func(T) error) error {
 	current := list.head
 	for current != nil {
 		if err := fn(current.value); err != nil {
@@ -186,23 +296,64 @@ func (list *SinglyLinkedList[T]) Traversal(fn func(T) error) error {
 }
 
 // ReverseTraversal is not applicable to singly linked lists and will result in a panic if called.
-func (list *SinglyLinkedList[T]) ReverseTraversal(fn func(T) error) error {
+func (list *singlyLinked[T]) ReverseTraversal(fn func(T) error) error {
 	panic("ReverseTraversal is not applicable to Singly Linked Lists")
 }
 
+// All returns an iterator over the values of the list, from the head to the tail.
+func (list *singlyLinked[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for current := list.head; current != nil; current = current.next {
+			if !yield(current.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the values of the list, from the tail to the head. Since nodes
+// only link forward, the values are first collected into a slice, so the iterator uses O(n) extra
+// memory.
+func (list *singlyLinked[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		values := make([]T, 0, list.size)
+		for current := list.head; current != nil; current = current.next {
+			values = append(values, current.value)
+		}
+		for i := len(values) - 1; i >= 0; i-- {
+			if !yield(values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over the index and value of each node, from the head to the tail.
+func (list *singlyLinked[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		index := 0
+		for current := list.head; current != nil; current = current.next {
+			if !yield(index, current.value) {
+				return
+			}
+			index++
+		}
+	}
+}
+
 // Size returns the size of the list (number of nodes).
-func (list *SinglyLinkedList[T]) Size() int {
+func (list *singlyLinked[T]) Size() int {
 	return list.size
 }
 
 // IsEmpty returns true if the list is empty, false otherwise.
-func (list *SinglyLinkedList[T]) IsEmpty() bool {
+func (list *singlyLinked[T]) IsEmpty() bool {
 	return list.size == 0
 }
 
 // String returns a string representation of the list, with each value followed by an arrow ("->")
 // pointing to the next value. The last value points to "nil", indicating the end of the list.
-func (list *SinglyLinkedList[T]) String() string {
+func (list *singlyLinked[T]) String() string {
 	var sb strings.Builder
 	current := list.head
 	for current != nil {
@@ -212,3 +363,20 @@ func (list *SinglyLinkedList[T]) String() string {
 	sb.WriteString("nil")
 	return sb.String()
 }
+
+// unlinkAfter removes the node that follows prev, or the head if prev is nil, fixing up the tail
+// pointer as needed.
+func (list *singlyLinked[T]) unlinkAfter(prev *Node[T]) {
+	if prev == nil {
+		list.head = list.head.next
+		if list.head == nil {
+			list.tail = nil
+		}
+	} else {
+		prev.next = prev.next.next
+		if prev.next == nil {
+			list.tail = prev
+		}
+	}
+	list.size--
+}
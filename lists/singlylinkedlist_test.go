@@ -17,6 +17,7 @@ package lists_test
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/f0rmiga/datanalgo/lists"
@@ -117,6 +118,43 @@ func TestInsertAtOutOfRange(t *testing.T) {
 	}
 }
 
+func TestIndexError(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+
+	err := list.InsertAt(3, 5)
+	if !errors.Is(err, lists.ErrIndexOutOfRange) {
+		t.Errorf("Expected ErrIndexOutOfRange, got: %v", err)
+	}
+
+	_, err = list.DeleteAt(-1)
+	var indexErr *lists.IndexError
+	if !errors.As(err, &indexErr) {
+		t.Fatalf("Expected *IndexError, got: %v", err)
+	}
+	if indexErr.Index != -1 || indexErr.Size != 2 {
+		t.Errorf("Unexpected index error: %+v", indexErr)
+	}
+}
+
+func TestEmptyListErrors(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+
+	if _, err := list.DeleteFirst(); !errors.Is(err, lists.ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList from DeleteFirst, got: %v", err)
+	}
+	if _, err := list.DeleteLast(); !errors.Is(err, lists.ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList from DeleteLast, got: %v", err)
+	}
+	if _, err := list.DeleteValue(1); !errors.Is(err, lists.ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList from DeleteValue, got: %v", err)
+	}
+	if _, err := list.Search(1); !errors.Is(err, lists.ErrEmptyList) {
+		t.Errorf("Expected ErrEmptyList from Search, got: %v", err)
+	}
+}
+
 func TestDeleteFirst(t *testing.T) {
 	list := &lists.SinglyLinkedList[int]{}
 	list.InsertFirst(3)
@@ -316,6 +354,86 @@ func TestSearchNotFound(t *testing.T) {
 	}
 }
 
+func TestIndexFunc(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(4)
+	list.InsertLast(6)
+
+	isEven := func(value int) bool { return value%2 == 0 }
+	if index := list.IndexFunc(isEven); index != 1 {
+		t.Errorf("Unexpected index: %d", index)
+	}
+	if !list.ContainsFunc(isEven) {
+		t.Error("Expected an even value to be found")
+	}
+
+	isNegative := func(value int) bool { return value < 0 }
+	if index := list.IndexFunc(isNegative); index != -1 {
+		t.Errorf("Unexpected index: %d", index)
+	}
+	if list.ContainsFunc(isNegative) {
+		t.Error("Expected no negative value to be found")
+	}
+}
+
+func TestDeleteFunc(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	for i := 1; i <= 6; i++ {
+		list.InsertLast(i)
+	}
+
+	deleted := list.DeleteFunc(func(value int) bool { return value%2 == 0 })
+
+	if deleted != 3 {
+		t.Errorf("Unexpected number of deleted values: %d", deleted)
+	}
+	if list.Size() != 3 {
+		t.Errorf("Unexpected size: %d", list.Size())
+	}
+
+	list.InsertLast(7)
+	if list.String() != "1 -> 3 -> 5 -> 7 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDeleteFuncAll(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+
+	deleted := list.DeleteFunc(func(int) bool { return true })
+
+	if deleted != 2 || !list.IsEmpty() {
+		t.Errorf("Unexpected result: deleted %d, list %s", deleted, list.String())
+	}
+
+	list.InsertLast(3)
+	if list.String() != "3 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestDeleteAll(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	for _, value := range []int{2, 1, 2, 3, 2} {
+		list.InsertLast(value)
+	}
+
+	deleted := list.DeleteAll(2)
+
+	if deleted != 3 {
+		t.Errorf("Unexpected number of deleted values: %d", deleted)
+	}
+	if list.String() != "1 -> 3 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+	if deleted := list.DeleteAll(4); deleted != 0 {
+		t.Errorf("Unexpected number of deleted values: %d", deleted)
+	}
+}
+
 func TestTraversal(t *testing.T) {
 	list := &lists.SinglyLinkedList[int]{}
 	list.InsertFirst(3)
@@ -370,6 +488,99 @@ func TestSinglyLinkedListReverseTraversal(t *testing.T) {
 	})
 }
 
+func TestAll(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.InsertLast(3)
+
+	actual := slices.Collect(list.All())
+	if !slices.Equal(actual, []int{1, 2, 3}) {
+		t.Errorf("Unexpected values: %v", actual)
+	}
+}
+
+func TestAllBreak(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.InsertLast(3)
+
+	var visited []int
+	for value := range list.All() {
+		visited = append(visited, value)
+		if value == 2 {
+			break
+		}
+	}
+
+	if !slices.Equal(visited, []int{1, 2}) {
+		t.Errorf("Unexpected visited values: %v", visited)
+	}
+}
+
+func TestBackward(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.InsertLast(3)
+
+	actual := slices.Collect(list.Backward())
+	if !slices.Equal(actual, []int{3, 2, 1}) {
+		t.Errorf("Unexpected values: %v", actual)
+	}
+}
+
+func TestBackwardBreak(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.InsertLast(3)
+
+	var visited []int
+	for value := range list.Backward() {
+		visited = append(visited, value)
+		break
+	}
+
+	if !slices.Equal(visited, []int{3}) {
+		t.Errorf("Unexpected visited values: %v", visited)
+	}
+}
+
+func TestEnumerate(t *testing.T) {
+	list := &lists.SinglyLinkedList[string]{}
+	list.InsertLast("a")
+	list.InsertLast("b")
+	list.InsertLast("c")
+
+	var visited []string
+	for index, value := range list.Enumerate() {
+		visited = append(visited, fmt.Sprintf("%d:%s", index, value))
+		if index == 1 {
+			break
+		}
+	}
+
+	if !slices.Equal(visited, []string{"0:a", "1:b"}) {
+		t.Errorf("Unexpected visited values: %v", visited)
+	}
+}
+
+func TestIteratorsEmptyList(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+
+	for range list.All() {
+		t.Error("Unexpected value from All")
+	}
+	for range list.Backward() {
+		t.Error("Unexpected value from Backward")
+	}
+	for range list.Enumerate() {
+		t.Error("Unexpected value from Enumerate")
+	}
+}
+
 func TestSize(t *testing.T) {
 	list := &lists.SinglyLinkedList[int]{}
 	list.InsertFirst(3)
@@ -502,6 +713,131 @@ func TestSinglyLinkedListWithCustomStruct(t *testing.T) {
 	}
 }
 
+func TestInsertLastAfterDeleteLast(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.InsertLast(3)
+	list.DeleteLast()
+	list.InsertLast(4)
+
+	if list.String() != "1 -> 2 -> 4 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestInsertLastAfterDeleteAtEnd(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.InsertLast(3)
+	list.DeleteAt(2)
+	list.InsertLast(4)
+
+	if list.String() != "1 -> 2 -> 4 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestInsertLastAfterDeleteValueAtTail(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.InsertLast(3)
+	list.DeleteValue(3)
+	list.InsertLast(4)
+
+	if list.String() != "1 -> 2 -> 4 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestInsertLastAfterEmptied(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.InsertLast(1)
+	list.InsertLast(2)
+	list.DeleteFirst()
+	list.DeleteValue(2)
+	list.InsertLast(3)
+	list.InsertAt(4, 1)
+	list.InsertLast(5)
+
+	if list.String() != "3 -> 4 -> 5 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+
+	if list.Size() != 3 {
+		t.Errorf("Unexpected size: %d", list.Size())
+	}
+}
+
+func BenchmarkInsertLast(b *testing.B) {
+	for _, n := range []int{1_000, 10_000, 100_000} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				list := &lists.SinglyLinkedList[int]{}
+				for j := 0; j < n; j++ {
+					list.InsertLast(j)
+				}
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/insert")
+		})
+	}
+}
+
+func BenchmarkInsertFirst(b *testing.B) {
+	for _, n := range []int{1_000, 10_000, 100_000} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				list := &lists.SinglyLinkedList[int]{}
+				for j := 0; j < n; j++ {
+					list.InsertFirst(j)
+				}
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/insert")
+		})
+	}
+}
+
+func TestSinglyLinkedListWithEqualityFunc(t *testing.T) {
+	list := lists.NewSinglyLinkedListFunc(slices.Equal[[]int])
+	list.InsertLast([]int{1})
+	list.InsertLast([]int{2, 3})
+	list.InsertLast([]int{4})
+
+	index, err := list.Search([]int{2, 3})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if index != 1 {
+		t.Errorf("Unexpected index: %d", index)
+	}
+
+	deleted, err := list.DeleteValue([]int{4})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !deleted {
+		t.Error("Expected value to be deleted")
+	}
+
+	if list.String() != "[1] -> [2 3] -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	const n = 10_000
+	list := &lists.SinglyLinkedList[int]{}
+	for i := 0; i < n; i++ {
+		list.InsertLast(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		list.Search(n - 1)
+	}
+}
+
 type Person struct {
 	Name string
 	Age  int
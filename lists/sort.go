@@ -0,0 +1,102 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lists
+
+// Sort sorts the list in ascending order as determined by the cmp function, which must return a
+// negative number when a < b, a positive number when a > b and zero when a == b. The sort is stable
+// and is implemented as a bottom-up merge sort that relinks the existing nodes, so it runs in
+// O(n log n) time with O(1) extra space.
+func (list *singlyLinked[T]) Sort(cmp func(a, b T) int) {
+	if list.size < 2 {
+		return
+	}
+	head := list.head
+	var tail *Node[T]
+	for width := 1; width < list.size; width *= 2 {
+		var sorted Node[T]
+		tail = &sorted
+		current := head
+		for current != nil {
+			left := current
+			right := cutAfter(left, width)
+			current = cutAfter(right, width)
+			tail.next, tail = mergeNodes(left, right, cmp)
+		}
+		head = sorted.next
+	}
+	list.head = head
+	list.tail = tail
+}
+
+// SortedInsert inserts a new node with the given value into a list that is already sorted by the
+// cmp function, keeping it sorted. The value is placed after any elements that compare equal to
+// it, so repeated calls preserve insertion order among equal elements. Inserting a value that is
+// not smaller than the last element takes constant time.
+func (list *singlyLinked[T]) SortedInsert(value T, cmp func(a, b T) int) {
+	if list.head == nil || cmp(list.tail.value, value) <= 0 {
+		list.InsertLast(value)
+		return
+	}
+	if cmp(value, list.head.value) < 0 {
+		list.InsertFirst(value)
+		return
+	}
+	current := list.head
+	for cmp(current.next.value, value) <= 0 {
+		current = current.next
+	}
+	current.next = &Node[T]{value: value, next: current.next}
+	list.size++
+}
+
+// cutAfter detaches the first n nodes starting at node from the rest of the chain and returns the
+// head of the rest, or nil if the chain has n nodes or fewer.
+func cutAfter[T any](node *Node[T], n int) *Node[T] {
+	for i := 1; node != nil && i < n; i++ {
+		node = node.next
+	}
+	if node == nil {
+		return nil
+	}
+	rest := node.next
+	node.next = nil
+	return rest
+}
+
+// mergeNodes merges two sorted chains of nodes into one, returning its head and tail. Nodes from
+// left are placed before nodes from right that compare equal, which keeps the merge stable.
+func mergeNodes[T any](left, right *Node[T], cmp func(a, b T) int) (head, tail *Node[T]) {
+	var merged Node[T]
+	tail = &merged
+	for left != nil && right != nil {
+		if cmp(left.value, right.value) <= 0 {
+			tail.next = left
+			left = left.next
+		} else {
+			tail.next = right
+			right = right.next
+		}
+		tail = tail.next
+	}
+	if left != nil {
+		tail.next = left
+	} else {
+		tail.next = right
+	}
+	for tail.next != nil {
+		tail = tail.next
+	}
+	return merged.next, tail
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lists_test
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/f0rmiga/datanalgo/lists"
+)
+
+func TestSort(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	for _, value := range []int{5, 3, 4, 1, 2} {
+		list.InsertLast(value)
+	}
+
+	list.Sort(cmp.Compare[int])
+
+	if list.String() != "1 -> 2 -> 3 -> 4 -> 5 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+
+	list.InsertLast(6)
+	if list.String() != "1 -> 2 -> 3 -> 4 -> 5 -> 6 -> nil" {
+		t.Errorf("Unexpected list state after InsertLast: %s", list.String())
+	}
+}
+
+func TestSortEmptyAndSingleElementList(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.Sort(cmp.Compare[int])
+
+	if !list.IsEmpty() {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+
+	list.InsertLast(1)
+	list.Sort(cmp.Compare[int])
+
+	if list.String() != "1 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+// TestSortProperties checks Sort against slices.SortStableFunc on random inputs. Elements carry
+// their original position so that any instability shows up as a mismatch.
+func TestSortProperties(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	byKey := func(a, b keyed) int { return cmp.Compare(a.key, b.key) }
+
+	for iteration := 0; iteration < 200; iteration++ {
+		n := rng.IntN(100)
+		expected := make([]keyed, n)
+		list := &lists.SinglyLinkedList[keyed]{}
+		for i := range expected {
+			expected[i] = keyed{key: rng.IntN(10), seq: i}
+			list.InsertLast(expected[i])
+		}
+
+		slices.SortStableFunc(expected, byKey)
+		list.Sort(byKey)
+
+		actual := slices.Collect(list.All())
+		if !slices.Equal(actual, expected) {
+			t.Fatalf("Sort mismatch for n=%d:\n got: %v\nwant: %v", n, actual, expected)
+		}
+		if list.Size() != n {
+			t.Fatalf("Unexpected size: %d, expected %d", list.Size(), n)
+		}
+		if n > 0 {
+			last, _ := list.DeleteLast()
+			if last != expected[n-1] {
+				t.Fatalf("Unexpected last element: %v, expected %v", last, expected[n-1])
+			}
+		}
+	}
+}
+
+func TestSortedInsert(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	for _, value := range []int{3, 1, 4, 1, 5, 9, 2, 6} {
+		list.SortedInsert(value, cmp.Compare[int])
+	}
+
+	if list.String() != "1 -> 1 -> 2 -> 3 -> 4 -> 5 -> 6 -> 9 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+
+	if list.Size() != 8 {
+		t.Errorf("Unexpected size: %d", list.Size())
+	}
+}
+
+func TestSortedInsertProperties(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	byKey := func(a, b keyed) int { return cmp.Compare(a.key, b.key) }
+
+	for iteration := 0; iteration < 200; iteration++ {
+		n := rng.IntN(50)
+		expected := make([]keyed, n)
+		list := &lists.SinglyLinkedList[keyed]{}
+		for i := range expected {
+			expected[i] = keyed{key: rng.IntN(10), seq: i}
+			list.SortedInsert(expected[i], byKey)
+		}
+
+		slices.SortStableFunc(expected, byKey)
+
+		actual := slices.Collect(list.All())
+		if !slices.Equal(actual, expected) {
+			t.Fatalf("SortedInsert mismatch for n=%d:\n got: %v\nwant: %v", n, actual, expected)
+		}
+	}
+}
+
+type keyed struct {
+	key int
+	seq int
+}
@@ -0,0 +1,161 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lists
+
+// Reverse reverses the order of the nodes in the list in place.
+func (list *singlyLinked[T]) Reverse() {
+	var prev *Node[T]
+	current := list.head
+	for current != nil {
+		next := current.next
+		current.next = prev
+		prev = current
+		current = next
+	}
+	list.head, list.tail = list.tail, list.head
+}
+
+// Concat moves all nodes of other to the end of the list in constant time, leaving other empty.
+// It panics if other is the list itself.
+func (list *SinglyLinkedList[T]) Concat(other *SinglyLinkedList[T]) {
+	list.concat(&other.singlyLinked)
+}
+
+// Concat moves all nodes of other to the end of the list in constant time, leaving other empty.
+// It panics if other is the list itself.
+func (list *SinglyLinkedListFunc[T]) Concat(other *SinglyLinkedListFunc[T]) {
+	list.concat(&other.singlyLinked)
+}
+
+// SplitAt splits the list at the specified index. The nodes from index onwards are moved to a new
+// list, which is returned, while the list keeps the nodes before index. Returns an *IndexError if
+// the index is out of range; an index equal to the size of the list returns an empty list.
+func (list *SinglyLinkedList[T]) SplitAt(index int) (*SinglyLinkedList[T], error) {
+	rest := &SinglyLinkedList[T]{}
+	if err := list.splitAt(index, &rest.singlyLinked); err != nil {
+		return nil, err
+	}
+	return rest, nil
+}
+
+// SplitAt splits the list at the specified index. The nodes from index onwards are moved to a new
+// list with the same eq function, which is returned, while the list keeps the nodes before index.
+// Returns an *IndexError if the index is out of range; an index equal to the size of the list
+// returns an empty list.
+func (list *SinglyLinkedListFunc[T]) SplitAt(index int) (*SinglyLinkedListFunc[T], error) {
+	rest := &SinglyLinkedListFunc[T]{eq: list.eq}
+	if err := list.splitAt(index, &rest.singlyLinked); err != nil {
+		return nil, err
+	}
+	return rest, nil
+}
+
+// Splice moves all nodes of other into the list at the specified index, leaving other empty. The
+// first node of other ends up at index. Returns an *IndexError if the index is out of range. It
+// panics if other is the list itself.
+func (list *SinglyLinkedList[T]) Splice(index int, other *SinglyLinkedList[T]) error {
+	return list.splice(index, &other.singlyLinked)
+}
+
+// Splice moves all nodes of other into the list at the specified index, leaving other empty. The
+// first node of other ends up at index. Returns an *IndexError if the index is out of range. It
+// panics if other is the list itself.
+func (list *SinglyLinkedListFunc[T]) Splice(index int, other *SinglyLinkedListFunc[T]) error {
+	return list.splice(index, &other.singlyLinked)
+}
+
+// concat implements Concat for both kinds of singly linked lists.
+func (list *singlyLinked[T]) concat(other *singlyLinked[T]) {
+	if other == list {
+		panic("cannot concatenate a list with itself")
+	}
+	if other.head == nil {
+		return
+	}
+	if list.head == nil {
+		list.head = other.head
+	} else {
+		list.tail.next = other.head
+	}
+	list.tail = other.tail
+	list.size += other.size
+	other.clear()
+}
+
+// splitAt implements SplitAt for both kinds of singly linked lists, moving the nodes from index
+// onwards to rest, which must be empty.
+func (list *singlyLinked[T]) splitAt(index int, rest *singlyLinked[T]) error {
+	if index < 0 || index > list.size {
+		return &IndexError{Index: index, Size: list.size}
+	}
+	if index == list.size {
+		return nil
+	}
+	if index == 0 {
+		*rest = *list
+		list.clear()
+		return nil
+	}
+	current := list.head
+	for i := 1; i < index; i++ {
+		current = current.next
+	}
+	rest.head = current.next
+	rest.tail = list.tail
+	rest.size = list.size - index
+	current.next = nil
+	list.tail = current
+	list.size = index
+	return nil
+}
+
+// splice implements Splice for both kinds of singly linked lists.
+func (list *singlyLinked[T]) splice(index int, other *singlyLinked[T]) error {
+	if other == list {
+		panic("cannot splice a list into itself")
+	}
+	if index < 0 || index > list.size {
+		return &IndexError{Index: index, Size: list.size}
+	}
+	if other.head == nil {
+		return nil
+	}
+	if index == list.size {
+		list.concat(other)
+		return nil
+	}
+	if index == 0 {
+		other.tail.next = list.head
+		list.head = other.head
+	} else {
+		current := list.head
+		for i := 1; i < index; i++ {
+			current = current.next
+		}
+		other.tail.next = current.next
+		current.next = other.head
+	}
+	list.size += other.size
+	other.clear()
+	return nil
+}
+
+// clear drops all nodes from the list without touching them, so they can be adopted by another
+// list.
+func (list *singlyLinked[T]) clear() {
+	list.head = nil
+	list.tail = nil
+	list.size = 0
+}
@@ -0,0 +1,195 @@
+// Copyright 2023 Thulio Ferraz Assis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lists_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/f0rmiga/datanalgo/lists"
+)
+
+func TestReverse(t *testing.T) {
+	list := newIntList(1, 2, 3)
+	list.Reverse()
+
+	if list.String() != "3 -> 2 -> 1 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+
+	list.InsertLast(0)
+	if list.String() != "3 -> 2 -> 1 -> 0 -> nil" {
+		t.Errorf("Unexpected list state after InsertLast: %s", list.String())
+	}
+}
+
+func TestReverseEmptyList(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.Reverse()
+	list.InsertLast(1)
+
+	if list.String() != "1 -> nil" {
+		t.Errorf("Unexpected list state: %s", list.String())
+	}
+}
+
+func TestConcat(t *testing.T) {
+	list := newIntList(1, 2)
+	other := newIntList(3, 4)
+	list.Concat(other)
+
+	assertList(t, list, "1 -> 2 -> 3 -> 4 -> nil", 4)
+	assertList(t, other, "nil", 0)
+
+	list.InsertLast(5)
+	other.InsertLast(6)
+	assertList(t, list, "1 -> 2 -> 3 -> 4 -> 5 -> nil", 5)
+	assertList(t, other, "6 -> nil", 1)
+}
+
+func TestConcatIntoEmptyList(t *testing.T) {
+	list := &lists.SinglyLinkedList[int]{}
+	list.Concat(newIntList(1, 2))
+	list.Concat(&lists.SinglyLinkedList[int]{})
+	list.InsertLast(3)
+
+	assertList(t, list, "1 -> 2 -> 3 -> nil", 3)
+}
+
+func TestConcatSelf(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic when concatenating a list with itself")
+		}
+	}()
+
+	list := newIntList(1)
+	list.Concat(list)
+}
+
+func TestSplitAt(t *testing.T) {
+	list := newIntList(1, 2, 3, 4)
+	rest, err := list.SplitAt(2)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertList(t, list, "1 -> 2 -> nil", 2)
+	assertList(t, rest, "3 -> 4 -> nil", 2)
+
+	list.InsertLast(5)
+	rest.InsertLast(6)
+	assertList(t, list, "1 -> 2 -> 5 -> nil", 3)
+	assertList(t, rest, "3 -> 4 -> 6 -> nil", 3)
+}
+
+func TestSplitAtKeepsEqualityFunc(t *testing.T) {
+	list := lists.NewSinglyLinkedListFunc(slices.Equal[[]int])
+	list.InsertLast([]int{1})
+	list.InsertLast([]int{2, 3})
+	rest, err := list.SplitAt(1)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if index, err := rest.Search([]int{2, 3}); err != nil || index != 0 {
+		t.Errorf("Unexpected search result: %d, %v", index, err)
+	}
+	if list.String() != "[1] -> nil" || rest.String() != "[2 3] -> nil" {
+		t.Errorf("Unexpected list states: %s and %s", list.String(), rest.String())
+	}
+}
+
+func TestSplitAtBounds(t *testing.T) {
+	list := newIntList(1, 2)
+
+	rest, err := list.SplitAt(2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertList(t, list, "1 -> 2 -> nil", 2)
+	assertList(t, rest, "nil", 0)
+
+	rest, err = list.SplitAt(0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertList(t, list, "nil", 0)
+	assertList(t, rest, "1 -> 2 -> nil", 2)
+
+	_, err = rest.SplitAt(3)
+	if !errors.Is(err, lists.ErrIndexOutOfRange) {
+		t.Errorf("Expected ErrIndexOutOfRange, got: %v", err)
+	}
+}
+
+func TestSplice(t *testing.T) {
+	list := newIntList(1, 4)
+	other := newIntList(2, 3)
+
+	if err := list.Splice(1, other); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertList(t, list, "1 -> 2 -> 3 -> 4 -> nil", 4)
+	assertList(t, other, "nil", 0)
+}
+
+func TestSpliceAtEnds(t *testing.T) {
+	list := newIntList(2)
+
+	if err := list.Splice(0, newIntList(0, 1)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := list.Splice(3, newIntList(3, 4)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	list.InsertLast(5)
+
+	assertList(t, list, "0 -> 1 -> 2 -> 3 -> 4 -> 5 -> nil", 6)
+}
+
+func TestSpliceOutOfRange(t *testing.T) {
+	list := newIntList(1)
+	other := newIntList(2)
+
+	err := list.Splice(2, other)
+
+	var indexErr *lists.IndexError
+	if !errors.As(err, &indexErr) {
+		t.Fatalf("Expected *IndexError, got: %v", err)
+	}
+	assertList(t, other, "2 -> nil", 1)
+}
+
+func newIntList(values ...int) *lists.SinglyLinkedList[int] {
+	list := &lists.SinglyLinkedList[int]{}
+	for _, value := range values {
+		list.InsertLast(value)
+	}
+	return list
+}
+
+func assertList(t *testing.T, list *lists.SinglyLinkedList[int], expected string, size int) {
+	t.Helper()
+	if list.String() != expected {
+		t.Errorf("Unexpected list state: %s, expected %s", list.String(), expected)
+	}
+	if list.Size() != size {
+		t.Errorf("Unexpected size: %d, expected %d", list.Size(), size)
+	}
+}